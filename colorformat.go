@@ -0,0 +1,330 @@
+package tailless
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var reOklch = regexp.MustCompile(`oklch\([^()]*\)`)
+var reRGB = regexp.MustCompile(`^rgba?\(\s*([\d.]+)[\s,]+([\d.]+)[\s,]+([\d.]+)\s*\)$`)
+
+// An sRGB color with gamma encoded components between 0 and 1.
+type rgbColor struct {
+	R, G, B float64
+}
+
+// An OKLCH color with the lightness between 0 and 1 and the hue in degrees.
+type oklchColor struct {
+	L, C, H float64
+}
+
+// formatColor converts a hex, rgb() or oklch() color to the hex, rgb or
+// oklch format. Colors outside the sRGB gamut are gamut mapped for the hex
+// and rgb formats. Other values, like currentColor, are returned as is.
+func formatColor(value string, format string) string {
+	rgb, ok := parseRGB(value)
+	if ok {
+		switch format {
+		case "hex":
+			return rgb.Hex()
+		case "rgb":
+			return rgb.String()
+		case "oklch":
+			return rgb.Oklch().String()
+		}
+
+		return value
+	}
+
+	color, ok := parseOklch(value)
+	if !ok {
+		return value
+	}
+
+	switch format {
+	case "hex":
+		return color.GamutMap().Hex()
+	case "rgb":
+		return color.GamutMap().String()
+	case "oklch":
+		return color.String()
+	}
+
+	return value
+}
+
+func validColorFormat(format string) error {
+	switch format {
+	case "", "hex", "rgb", "oklch":
+		return nil
+	}
+
+	return fmt.Errorf("Unknown color format '%s'", format)
+}
+
+// addColorFallbacks adds a declaration with hex colors before every
+// declaration that uses oklch() colors, for browsers that don't support
+// them. The browser doesn't validate the values of custom properties, so
+// for them it would keep the oklch() color and drop the declarations that
+// use it. Their hex colors go in an @supports rule instead.
+func addColorFallbacks(n node) {
+	children := make([]node, 0)
+	supports := newAtRuleNode("@supports not (color: oklch(0 0 0))", 0)
+
+	for _, child := range n.GetChildren() {
+		declaration, ok := child.(*declarationNode)
+		if ok && reOklch.MatchString(declaration.Text) {
+			text := reOklch.ReplaceAllStringFunc(declaration.Text, oklchFallback)
+			fallback := newDeclarationNode(text, declaration.LineNumber)
+
+			if strings.HasPrefix(declaration.Text, "--") {
+				supports.AddChild(fallback)
+			} else {
+				children = append(children, fallback)
+			}
+		}
+
+		addColorFallbacks(child)
+
+		children = append(children, child)
+	}
+
+	if len(supports.Children) > 0 {
+		children = append(children, supports)
+	}
+
+	n.SetChildren(children)
+}
+
+func oklchFallback(value string) string {
+	color, alpha, ok := strings.Cut(strings.TrimSuffix(value, ")"), "/")
+	if !ok {
+		return formatColor(value, "hex")
+	}
+
+	rgb := formatColor(color+")", "rgb")
+	return strings.TrimSuffix(rgb, ")") + " / " + strings.TrimSpace(alpha) + ")"
+}
+
+func parseOklch(value string) (oklchColor, bool) {
+	if !strings.HasPrefix(value, "oklch(") || !strings.HasSuffix(value, ")") {
+		return oklchColor{}, false
+	}
+
+	fields := strings.Fields(value[6 : len(value)-1])
+	if len(fields) != 3 {
+		return oklchColor{}, false
+	}
+
+	numbers := make([]float64, 3)
+	for i, field := range fields {
+		percentage := strings.HasSuffix(field, "%")
+
+		number, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		if err != nil {
+			return oklchColor{}, false
+		}
+
+		if percentage {
+			number /= 100
+		}
+
+		numbers[i] = number
+	}
+
+	return oklchColor{numbers[0], numbers[1], numbers[2]}, true
+}
+
+func parseRGB(value string) (rgbColor, bool) {
+	r, g, b, ok := parseHexColor(value)
+	if ok {
+		return rgbColor{float64(r) / 255, float64(g) / 255, float64(b) / 255}, true
+	}
+
+	match := reRGB.FindStringSubmatch(value)
+	if match == nil {
+		return rgbColor{}, false
+	}
+
+	components := make([]float64, 3)
+	for i := range components {
+		component, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return rgbColor{}, false
+		}
+
+		components[i] = component / 255
+	}
+
+	return rgbColor{components[0], components[1], components[2]}, true
+}
+
+func (c oklchColor) String() string {
+	return fmt.Sprintf("oklch(%s%% %s %s)", formatNumber(c.L*100, 2), formatNumber(c.C, 4), formatNumber(c.H, 3))
+}
+
+func (c oklchColor) lab() (float64, float64, float64) {
+	hue := c.H * math.Pi / 180
+	return c.L, c.C * math.Cos(hue), c.C * math.Sin(hue)
+}
+
+// RGB converts the color to sRGB, without clipping the components.
+func (c oklchColor) RGB() rgbColor {
+	L, a, b := c.lab()
+
+	l := math.Pow(L+0.3963377774*a+0.2158037573*b, 3)
+	m := math.Pow(L-0.1055613458*a-0.0638541728*b, 3)
+	s := math.Pow(L-0.0894841775*a-1.2914855480*b, 3)
+
+	return rgbColor{
+		gammaEncode(4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		gammaEncode(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		gammaEncode(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s),
+	}
+}
+
+// GamutMap converts the color to sRGB, reducing its chroma until it fits in
+// the sRGB gamut, as described in CSS Color Module Level 4.
+func (c oklchColor) GamutMap() rgbColor {
+	const jnd = 0.02
+	const epsilon = 0.0001
+
+	if c.L >= 1 {
+		return rgbColor{1, 1, 1}
+	}
+
+	if c.L <= 0 {
+		return rgbColor{0, 0, 0}
+	}
+
+	if c.RGB().InGamut() {
+		return c.RGB()
+	}
+
+	clipped := c.RGB().Clip()
+	if deltaEOK(clipped.Oklch(), c) < jnd {
+		return clipped
+	}
+
+	current := c
+	low := 0.0
+	high := c.C
+	lowInGamut := true
+
+	for high-low > epsilon {
+		current.C = (low + high) / 2
+
+		if lowInGamut && current.RGB().InGamut() {
+			low = current.C
+			continue
+		}
+
+		clipped = current.RGB().Clip()
+		e := deltaEOK(clipped.Oklch(), current)
+		if e < jnd {
+			if jnd-e < epsilon {
+				return clipped
+			}
+
+			lowInGamut = false
+			low = current.C
+		} else {
+			high = current.C
+		}
+	}
+
+	return clipped
+}
+
+func (c rgbColor) InGamut() bool {
+	const epsilon = 0.000001
+
+	for _, component := range []float64{c.R, c.G, c.B} {
+		if component < -epsilon || component > 1+epsilon {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c rgbColor) Clip() rgbColor {
+	return rgbColor{clamp(c.R), clamp(c.G), clamp(c.B)}
+}
+
+func (c rgbColor) Oklch() oklchColor {
+	r := gammaDecode(c.R)
+	g := gammaDecode(c.G)
+	b := gammaDecode(c.B)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	L := 0.2104542553*l + 0.7936177850*m - 0.0040720468*s
+	A := 1.9779984951*l - 2.4285922050*m + 0.4505937099*s
+	B := 0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+
+	chroma := math.Hypot(A, B)
+	hue := math.Atan2(B, A) * 180 / math.Pi
+	if hue < 0 {
+		hue += 360
+	}
+
+	// Achromatic colors have no meaningful hue.
+	if chroma < 0.0001 {
+		chroma = 0
+		hue = 0
+	}
+
+	return oklchColor{L, chroma, hue}
+}
+
+func (c rgbColor) bytes() (int, int, int) {
+	c = c.Clip()
+	return int(math.Round(c.R * 255)), int(math.Round(c.G * 255)), int(math.Round(c.B * 255))
+}
+
+func (c rgbColor) Hex() string {
+	r, g, b := c.bytes()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+func (c rgbColor) String() string {
+	r, g, b := c.bytes()
+	return fmt.Sprintf("rgb(%d %d %d)", r, g, b)
+}
+
+func deltaEOK(a oklchColor, b oklchColor) float64 {
+	L1, a1, b1 := a.lab()
+	L2, a2, b2 := b.lab()
+
+	return math.Sqrt((L1-L2)*(L1-L2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+func gammaEncode(c float64) float64 {
+	if math.Abs(c) <= 0.0031308 {
+		return 12.92 * c
+	}
+
+	return math.Copysign(1.055*math.Pow(math.Abs(c), 1/2.4)-0.055, c)
+}
+
+func gammaDecode(c float64) float64 {
+	if math.Abs(c) <= 0.04045 {
+		return c / 12.92
+	}
+
+	return math.Copysign(math.Pow((math.Abs(c)+0.055)/1.055, 2.4), c)
+}
+
+func clamp(c float64) float64 {
+	return math.Min(math.Max(c, 0), 1)
+}
+
+func formatNumber(number float64, decimals int) string {
+	return strconv.FormatFloat(math.Round(number*math.Pow(10, float64(decimals)))/math.Pow(10, float64(decimals)), 'f', -1, 64)
+}
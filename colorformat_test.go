@@ -0,0 +1,102 @@
+package tailless
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatColor(t *testing.T) {
+	tests := []struct {
+		value    string
+		format   string
+		expected string
+	}{
+		{"oklch(63.7% 0.237 25.331)", "hex", "#fb2c36"},
+		{"oklch(50.8% 0.118 165.612)", "hex", "#007a55"},
+		{"oklch(63.7% 0.237 25.331)", "rgb", "rgb(251 44 54)"},
+		{"#ef4444", "rgb", "rgb(239 68 68)"},
+		{"rgb(239 68 68)", "hex", "#ef4444"},
+		{"#ef4444", "oklch", "oklch(63.68% 0.2078 25.331)"},
+		{"oklch(63.68% 0.2078 25.331)", "hex", "#ef4444"},
+		{"#ffffff", "oklch", "oklch(100% 0 0)"},
+		{"#000000", "oklch", "oklch(0% 0 0)"},
+		{"currentColor", "hex", "currentColor"},
+	}
+
+	for _, test := range tests {
+		actual := formatColor(test.value, test.format)
+		if actual != test.expected {
+			t.Errorf("%s as %s: expected %s, got %s", test.value, test.format, test.expected, actual)
+		}
+	}
+}
+
+func TestFormatColorRoundTrip(t *testing.T) {
+	for name, hex := range *colors {
+		if !strings.HasPrefix(hex, "#") {
+			continue
+		}
+
+		oklch := formatColor(hex, "oklch")
+
+		actual := formatColor(oklch, "hex")
+		if actual != hex {
+			t.Errorf("%s: %s to %s: expected %s, got %s", name, hex, oklch, hex, actual)
+		}
+	}
+}
+
+func TestCustomPropertyColorFallbacks(t *testing.T) {
+	tests := []struct {
+		less     string
+		options  Options
+		expected string
+	}{
+		{
+			".a\n{\n    .ring-red-500;\n}\n",
+			Options{Palette: "v4"},
+			".a {\n  --tw-ring-color: oklch(63.7% 0.237 25.331);\n}\n@supports not (color: oklch(0 0 0)) {\n.a {\n  --tw-ring-color: #fb2c36;\n}\n}\n",
+		},
+		{
+			".a\n{\n    .from-red-500/50;\n}\n",
+			Options{Palette: "v4"},
+			".a {\n  --tw-gradient-from: oklch(63.7% 0.237 25.331 / 0.5);\n}\n@supports not (color: oklch(0 0 0)) {\n.a {\n  --tw-gradient-from: rgb(251 44 54 / 0.5);\n}\n}\n",
+		},
+		{
+			".a\n{\n    .md:ring-red-500;\n}\n",
+			Options{Palette: "v4"},
+			"@media (min-width: 768px) {\n.a {\n  --tw-ring-color: oklch(63.7% 0.237 25.331);\n}\n@supports not (color: oklch(0 0 0)) {\n.a {\n  --tw-ring-color: #fb2c36;\n}\n}\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		css, err := parseString(t, test.less, test.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The @property rules follow the rule.
+		if !strings.HasPrefix(css, test.expected) {
+			t.Errorf("expected\n%s\ngot\n%s", test.expected, css)
+		}
+	}
+}
+
+func TestThemeColorFallbacks(t *testing.T) {
+	rule := newSelectorNode([]string{":root"}, 0)
+	rule.AddChild(newDeclarationNode("--color-red-500: oklch(63.7% 0.237 25.331);", 0))
+	rule.AddChild(newDeclarationNode("--spacing-4: 1rem;", 0))
+
+	tree := rootNode{}
+	tree.AddChild(rule)
+	addColorFallbacks(&tree)
+	expandSelectors(&tree)
+
+	var b strings.Builder
+	tree.Render(&b)
+
+	expected := ":root {\n  --color-red-500: oklch(63.7% 0.237 25.331);\n  --spacing-4: 1rem;\n}\n@supports not (color: oklch(0 0 0)) {\n:root {\n  --color-red-500: #fb2c36;\n}\n}\n"
+	if b.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b.String())
+	}
+}
@@ -0,0 +1,245 @@
+package tailless
+
+import (
+	"cmp"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const candidateCharacters = `!A-Za-z0-9_\-:/.\[\]%#()=&>~+*@`
+
+var reWidthQuery = regexp.MustCompile(`^@(media|container)\b.*\((min|max)-width: ([^)]+)\)$`)
+
+// A generated rule with its position in the output. Plain utilities come
+// first, then utilities with variants, then the screen variants and the
+// container variants, ordered by width, so "md:p-8" overrides "p-4".
+type generatedRule struct {
+	Rule  node
+	Group int
+	Width float64
+}
+
+// GenerateUtilities scans the files matching the content globs of the
+// options for class names and adds a rule for every class that is a
+// Tailwind utility, at the "@tailwind utilities;" directive or else at the
+// end of the output.
+func (p *parser) GenerateUtilities(tree *rootNode, tailwind *tailwindCollection) error {
+	if len(p.Options.Content) == 0 {
+		return nil
+	}
+
+	candidates, err := scanCandidates(p.Options.Content, p.separator())
+	if err != nil {
+		return err
+	}
+
+	var target node = tree
+	for _, child := range tree.Children {
+		if child.GetType() == "tailwind-utilities" {
+			target = child
+		}
+	}
+
+	rules := make([]generatedRule, 0)
+
+	for _, candidate := range candidates {
+		utility := tailwind.Get("." + candidate)
+		if utility == nil {
+			continue
+		}
+
+		rule := newSelectorNode([]string{"." + escapeClassName(candidate)}, 0)
+		rule.Children = utility.Children
+
+		group, width := tailwind.variantOrder(candidate)
+		rules = append(rules, generatedRule{rule, group, width})
+	}
+
+	slices.SortStableFunc(rules, func(a, b generatedRule) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.Width, b.Width))
+	})
+
+	for _, rule := range rules {
+		target.AddChild(rule.Rule)
+	}
+
+	return nil
+}
+
+// variantOrder returns the group and the width that order the rule of a
+// class name. The max-width container variants are ordered from wide to
+// narrow, so they are sorted by the negated width.
+func (t *tailwindCollection) variantOrder(name string) (int, float64) {
+	variants, _ := splitVariants(name, t.Separator)
+	if len(variants) == 0 {
+		return 0, 0
+	}
+
+	group, width := 1, 0.0
+
+	for _, name := range variants {
+		variant, _ := t.getVariant(name)
+
+		match := reWidthQuery.FindStringSubmatch(variant)
+		if match == nil {
+			continue
+		}
+
+		g, w := 2, cssLength(match[3])
+		switch {
+		case match[1] == "container" && match[2] == "max":
+			g, w = 3, -w
+		case match[1] == "container":
+			g = 4
+		case match[2] == "max":
+			continue
+		}
+
+		if g > group || (g == group && w > width) {
+			group, width = g, w
+		}
+	}
+
+	return group, width
+}
+
+// scanCandidates returns the possible class names in the files matching the
+// globs, in the order in which they are found.
+func scanCandidates(globs []string, separator string) ([]string, error) {
+	reCandidate := regexp.MustCompile("[" + candidateCharacters + regexp.QuoteMeta(separator) + "]+")
+
+	candidates := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, glob := range globs {
+		filenames, err := globFiles(glob)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filename := range filenames {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, candidate := range reCandidate.FindAllString(string(data), -1) {
+				candidate = strings.TrimRight(candidate, ".:"+separator)
+				if candidate == "" || seen[candidate] {
+					continue
+				}
+
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// globFiles returns the files matching a glob, in which ** matches any
+// number of directories, like "templates/**/*.html".
+func globFiles(glob string) ([]string, error) {
+	glob = filepath.ToSlash(glob)
+
+	root := "."
+	segments := strings.Split(glob, "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "*?[") {
+			if i > 0 {
+				root = strings.Join(segments[:i], "/")
+				if root == "" {
+					root = "/"
+				}
+			}
+			break
+		}
+	}
+
+	re, err := regexp.Compile(globToRegexp(glob))
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0)
+
+	// A glob in a directory that doesn't exist matches no files.
+	_, err = os.Stat(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return filenames, nil
+	}
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() && re.MatchString(filepath.ToSlash(path)) {
+			filenames = append(filenames, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(filenames)
+
+	return filenames, nil
+}
+
+func globToRegexp(glob string) string {
+	glob = strings.TrimPrefix(glob, "./")
+
+	var b strings.Builder
+	b.WriteString("^(\\./)?")
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// escapeClassName escapes the characters of a class name that have a
+// meaning in selectors, so "md:w-1/2" becomes "md\:w-1\/2".
+func escapeClassName(name string) string {
+	var b strings.Builder
+
+	for i, c := range name {
+		switch {
+		case c >= '0' && c <= '9' && i == 0:
+			b.WriteString(`\3` + string(c) + " ")
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c > 127:
+			b.WriteRune(c)
+		default:
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		}
+	}
+
+	return b.String()
+}
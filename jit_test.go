@@ -0,0 +1,48 @@
+package tailless
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGenerateUtilitiesOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	html := `<div class="lg:p-2 md:p-8 @md:p-5 @max-md:p-6 @max-lg:p-7 hover:p-1 p-4">`
+	err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(html), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := newParser(Options{Content: []string{filepath.Join(dir, "*.html"), filepath.Join(dir, "missing/**/*.html")}})
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	tree := rootNode{}
+	err = p.GenerateUtilities(&tree, tailwind)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selectors := make([]string, 0)
+	for _, child := range tree.Children {
+		selectors = append(selectors, child.(*selectorNode).Selectors[0])
+	}
+
+	expected := []string{`.p-4`, `.hover\:p-1`, `.md\:p-8`, `.lg\:p-2`, `.\@max-lg\:p-7`, `.\@max-md\:p-6`, `.\@md\:p-5`}
+	if !slices.Equal(selectors, expected) {
+		t.Errorf("expected %v, got %v", expected, selectors)
+	}
+}
+
+func TestGlobFilesMissingDirectory(t *testing.T) {
+	filenames, err := globFiles(filepath.Join(t.TempDir(), "missing/**/*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(filenames) != 0 {
+		t.Errorf("expected no files, got %v", filenames)
+	}
+}
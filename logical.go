@@ -0,0 +1,100 @@
+package tailless
+
+import "strings"
+
+// The physical properties of the logical properties, for left-to-right and
+// right-to-left text.
+var logicalProperties = map[string][2]string{
+	"margin-inline-start":       {"margin-left", "margin-right"},
+	"margin-inline-end":         {"margin-right", "margin-left"},
+	"padding-inline-start":      {"padding-left", "padding-right"},
+	"padding-inline-end":        {"padding-right", "padding-left"},
+	"inset-inline-start":        {"left", "right"},
+	"inset-inline-end":          {"right", "left"},
+	"border-inline-start-width": {"border-left-width", "border-right-width"},
+	"border-inline-end-width":   {"border-right-width", "border-left-width"},
+	"border-inline-start-color": {"border-left-color", "border-right-color"},
+	"border-inline-end-color":   {"border-right-color", "border-left-color"},
+	"border-start-start-radius": {"border-top-left-radius", "border-top-right-radius"},
+	"border-start-end-radius":   {"border-top-right-radius", "border-top-left-radius"},
+	"border-end-start-radius":   {"border-bottom-left-radius", "border-bottom-right-radius"},
+	"border-end-end-radius":     {"border-bottom-right-radius", "border-bottom-left-radius"},
+}
+
+// The physical values of the logical text-align values.
+var logicalTextAlign = map[string][2]string{
+	"start": {"left", "right"},
+	"end":   {"right", "left"},
+}
+
+// physicalFallbacks returns rules with the physical properties of the
+// logical declarations, under [dir="ltr"] and mirrored under [dir="rtl"],
+// for browsers that don't support logical properties. Utilities with an ltr
+// or rtl variant get the physical declarations of that direction.
+func physicalFallbacks(declarations []node, direction string) []node {
+	fallbacks := make([]node, 0)
+
+	for i, dir := range []string{"ltr", "rtl"} {
+		if direction != "" && direction != dir {
+			continue
+		}
+
+		physical := make([]node, 0)
+
+		for _, d := range declarations {
+			declaration, ok := d.(*declarationNode)
+			if !ok {
+				continue
+			}
+
+			text, ok := physicalDeclaration(declaration.Text, i)
+			if ok {
+				physical = append(physical, newDeclarationNode(text, declaration.LineNumber))
+			}
+		}
+
+		if direction != "" {
+			return physical
+		}
+
+		if len(physical) > 0 {
+			rule := newSelectorNode([]string{`[dir="` + dir + `"] &`}, 0)
+			rule.Children = physical
+			fallbacks = append(fallbacks, rule)
+		}
+	}
+
+	return fallbacks
+}
+
+func physicalDeclaration(text string, direction int) (string, bool) {
+	property, value, ok := strings.Cut(text, ":")
+	if !ok {
+		return "", false
+	}
+
+	property = strings.TrimSpace(property)
+
+	physical, ok := logicalProperties[property]
+	if ok {
+		return physical[direction] + ":" + value, true
+	}
+
+	if property != "text-align" {
+		return "", false
+	}
+
+	align, rest, _ := strings.Cut(strings.TrimSpace(value), ";")
+	align, important, _ := strings.Cut(align, " ")
+
+	physical, ok = logicalTextAlign[align]
+	if !ok {
+		return "", false
+	}
+
+	if important != "" {
+		important = " " + important
+	}
+
+	return "text-align: " + physical[direction] + important + ";" + rest, true
+}
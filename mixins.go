@@ -1,14 +1,54 @@
 package tailless
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type mixins interface {
 	Get(string) *selectorNode
 	Set(string, *selectorNode)
 }
 
-func resolveMixins(tree *rootNode) error {
-	return recursiveResolveMixins(tree, nil, newTailwindCollection())
+func resolveMixins(tree *rootNode, tailwind *tailwindCollection) error {
+	return recursiveResolveMixins(tree, nil, tailwind)
+}
+
+// readUtilities moves the utilities defined with "@utility name { ... }" from
+// the tree to the tailwind collection, so they can be used with variants and
+// in templates like the built-in utilities.
+func readUtilities(tree *rootNode, tailwind *tailwindCollection) error {
+	rootMixins := newMixinsCollection(nil)
+	rootMixins.Read(tree)
+
+	newChildren := make([]node, 0)
+
+	for _, child := range tree.Children {
+		atRule, ok := child.(*atRuleNode)
+		if !ok || !strings.HasPrefix(atRule.Text, "@utility ") {
+			newChildren = append(newChildren, child)
+			continue
+		}
+
+		name := strings.TrimSpace(strings.TrimPrefix(atRule.Text, "@utility"))
+		if name == "" || strings.ContainsAny(name, " .,:") {
+			return fmt.Errorf("Line %d: Invalid utility name '%s'", atRule.LineNumber, name)
+		}
+
+		utility := newSelectorNode(nil, atRule.LineNumber)
+		utility.Children = atRule.Children
+
+		err := recursiveResolveMixins(utility, rootMixins, tailwind)
+		if err != nil {
+			return err
+		}
+
+		tailwind.Set("."+name, utility)
+	}
+
+	tree.Children = newChildren
+
+	return nil
 }
 
 func recursiveResolveMixins(n node, parentMixins mixins, twMixins mixins) error {
@@ -0,0 +1,118 @@
+package tailless
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rePaletteFunction = regexp.MustCompile(`^palette\(\s*([^()]*(?:\([^()]*\))?)\s*\)$`)
+
+// The lightness and the relative chroma of the shades of a generated
+// palette, following the Tailwind v4 palette.
+var paletteShades = []struct {
+	Shade     int
+	Lightness float64
+	Chroma    float64
+}{
+	{50, 0.975, 0.08},
+	{100, 0.945, 0.18},
+	{200, 0.9, 0.32},
+	{300, 0.83, 0.5},
+	{400, 0.74, 0.8},
+	{500, 0.66, 1},
+	{600, 0.58, 1.05},
+	{700, 0.5, 0.95},
+	{800, 0.44, 0.8},
+	{900, 0.39, 0.62},
+	{950, 0.27, 0.4},
+}
+
+// generatePalette generates a 50-950 color scale from a single color. The
+// shades have the lightness of the Tailwind palette and the hue of the
+// color, and the color itself becomes the shade that is closest in
+// lightness.
+func generatePalette(base string) (stringMap, error) {
+	color, ok := parseOklch(base)
+	if !ok {
+		rgb, ok := parseRGB(base)
+		if !ok {
+			return nil, fmt.Errorf("Invalid palette color '%s'", base)
+		}
+
+		color = rgb.Oklch()
+	}
+
+	nearest := 0
+	for i, s := range paletteShades {
+		if math.Abs(s.Lightness-color.L) < math.Abs(paletteShades[nearest].Lightness-color.L) {
+			nearest = i
+		}
+	}
+
+	chroma := color.C / paletteShades[nearest].Chroma
+
+	palette := make(stringMap)
+	for i, s := range paletteShades {
+		name := strconv.Itoa(s.Shade)
+
+		if i == nearest {
+			palette[name] = base
+			continue
+		}
+
+		shade := oklchColor{s.Lightness, chroma * s.Chroma, color.H}
+		palette[name] = shade.GamutMap().Hex()
+	}
+
+	return palette, nil
+}
+
+// paletteArgument returns the color of "palette(#0f766e)".
+func paletteArgument(value string) (string, bool) {
+	match := rePaletteFunction.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+// ReadPalettes adds the colors of the variables defined with palette(), like
+// "@brand: palette(#0f766e);", to the theme, as brand and brand-50 to
+// brand-950. The variable itself becomes the color.
+func (p *parser) ReadPalettes(elements *elements) error {
+	for i, element := range elements.Items {
+		if element.ElementType != typeVariable {
+			continue
+		}
+
+		name, value, ok := strings.Cut(element.Text, ":")
+		if !ok {
+			continue
+		}
+
+		base, ok := paletteArgument(strings.TrimSuffix(strings.TrimSpace(value), ";"))
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+
+		palette, err := generatePalette(base)
+		if err != nil {
+			return fmt.Errorf("Line %d: %v", element.LineNumber, err)
+		}
+
+		p.Theme.Colors[name] = base
+		for shade, color := range palette {
+			p.Theme.Colors[name+"-"+shade] = color
+		}
+
+		elements.Items[i].Text = "@" + name + ": " + base + ";"
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package tailless
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The palette() of a theme file and of a variable define the same colors.
+func TestPaletteNames(t *testing.T) {
+	themeFile := filepath.Join(t.TempDir(), "theme.json")
+
+	err := os.WriteFile(themeFile, []byte(`{ "extend": { "colors": { "brand": "palette(#0f766e)" } } }`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	less := ".a\n{\n    .bg-brand;\n    .text-brand-50;\n}\n"
+	expected := ".a {\n  background-color: #0f766e;\n  color: #f2f8f7;\n}\n"
+
+	tests := []struct {
+		name    string
+		less    string
+		options Options
+	}{
+		{"theme file", less, Options{ThemeFile: themeFile}},
+		{"variable", "@brand: palette(#0f766e);\n" + less, Options{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			css, err := parseString(t, test.less, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if css != expected {
+				t.Errorf("expected\n%s\ngot\n%s", expected, css)
+			}
+		})
+	}
+}
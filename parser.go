@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -18,6 +20,9 @@ const (
 	typeCloseBrace  = 6
 	typeImport      = 7
 	typeMixin       = 8
+	typeConfig      = 9
+	typeTailwind    = 10
+	typeVariant     = 11
 )
 
 var reVariable = regexp.MustCompile(`@[0-9A-Za-z-_]+`)
@@ -25,6 +30,9 @@ var reBraces = regexp.MustCompile(`[\{\}]`)
 
 type parser struct {
 	Elements *[]element
+	Options  Options
+	Theme    *theme
+	Variants stringMap
 }
 
 type line struct {
@@ -59,11 +67,43 @@ func newElements() *elements {
 	return &elements
 }
 
-func newParser() *parser {
-	return &parser{}
+func newParser(options Options) *parser {
+	return &parser{Options: options, Variants: make(stringMap)}
+}
+
+// separator returns the separator between the variants and the utility.
+func (p *parser) separator() string {
+	if p.Options.Separator == "" {
+		return ":"
+	}
+
+	return p.Options.Separator
+}
+
+// colorFormat returns the format of the output colors, which for the v4
+// palette is oklch unless another format is chosen.
+func (p *parser) colorFormat() string {
+	if p.Options.ColorFormat == "" && p.Options.Palette == "v4" {
+		return "oklch"
+	}
+
+	return p.Options.ColorFormat
 }
 
 func (p *parser) Parse(srcFilename string, destFilename string) error {
+	p.Theme = newDefaultTheme()
+	err := p.Theme.SetPalette(p.Options.Palette)
+	if err != nil {
+		return err
+	}
+
+	if p.Options.ThemeFile != "" {
+		err = p.Theme.Load(p.Options.ThemeFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	lines, err := p.RemoveComments(srcFilename)
 	if err != nil {
 		return err
@@ -84,21 +124,68 @@ func (p *parser) Parse(srcFilename string, destFilename string) error {
 		return err
 	}
 
+	err = p.ReadConfig(elements, srcFilename)
+	if err != nil {
+		return err
+	}
+
+	err = p.ReadVariants(elements)
+	if err != nil {
+		return err
+	}
+
+	err = p.ReadPalettes(elements)
+	if err != nil {
+		return err
+	}
+
+	err = p.Theme.FormatColors(p.Options.ColorFormat)
+	if err != nil {
+		return err
+	}
+
 	tree, err := p.BuildTree(elements)
 	if err != nil {
 		return err
 	}
 
-	err = resolveMixins(tree)
+	p.Theme.CustomProperties = p.Options.CustomProperties
+
+	tailwind := newTailwindCollection(p.Theme, p.Options.Prefix, p.separator())
+	tailwind.LogicalFallbacks = p.Options.LogicalFallbacks
+	maps.Copy(tailwind.Variants, p.Variants)
+
+	err = readUtilities(tree, tailwind)
+	if err != nil {
+		return err
+	}
+
+	err = resolveMixins(tree, tailwind)
+	if err != nil {
+		return err
+	}
+
+	err = p.GenerateUtilities(tree, tailwind)
 	if err != nil {
 		return err
 	}
 
-	err = resolveVariables(tree)
+	tree.Children = append(tree.Children, tailwind.GetProperties()...)
+	tree.Children = append(tree.Children, tailwind.GetKeyframes()...)
+
+	if p.Options.CustomProperties {
+		addThemeProperties(tree, p.Theme)
+	}
+
+	err = resolveVariables(tree, p.Theme)
 	if err != nil {
 		return err
 	}
 
+	if p.colorFormat() == "oklch" {
+		addColorFallbacks(tree)
+	}
+
 	expandSelectors(tree)
 
 	tree.HideIfEmpty()
@@ -245,8 +332,21 @@ func (p *parser) SplitIntoElements(lines *lines) (*elements, error) {
 		if isVariable(str) {
 			elements.Add(str, typeVariable, line.LineNumber)
 		} else if isAtRule(str) {
-			if str[:7] == "@import" {
+			if strings.HasPrefix(str, "@import") {
 				elements.Add(str, typeImport, line.LineNumber)
+			} else if strings.HasPrefix(str, "@config ") {
+				elements.Add(str, typeConfig, line.LineNumber)
+			} else if strings.HasPrefix(str, "@variant ") {
+				elements.Add(str, typeVariant, line.LineNumber)
+			} else if strings.HasPrefix(str, "@tailwind ") {
+				elements.Add(str, typeTailwind, line.LineNumber)
+			} else if strings.HasPrefix(str, "@apply ") {
+				mixins, err := applyToMixins(str, p.separator())
+				if err != nil {
+					return nil, fmt.Errorf("Line %d: %v", line.LineNumber, err)
+				}
+
+				elements.Add(mixins, typeMixin, line.LineNumber)
 			} else {
 				elements.Add(str, typeAtRule, line.LineNumber)
 			}
@@ -306,6 +406,10 @@ func (p *parser) ValidateElements(elements *elements) error {
 			}
 		}
 
+		if elementType == typeVariant && nextElementType == typeOpenBrace {
+			return fmt.Errorf("Line %d: Invalid variant, %s", item.LineNumber, variantSyntax)
+		}
+
 		if nextElementType == typeOpenBrace {
 			if elementType != typeAtRule && elementType != typeSelector {
 				fmt.Println("Type: ", elementType)
@@ -321,6 +425,109 @@ func (p *parser) ValidateElements(elements *elements) error {
 	return nil
 }
 
+// ReadConfig loads the theme files referenced by @config "theme.json";
+// relative to the directory of the source file.
+func (p *parser) ReadConfig(elements *elements, srcFilename string) error {
+	for _, element := range elements.Items {
+		if element.ElementType != typeConfig {
+			continue
+		}
+
+		if !endsWithSemiColon(element.Text) {
+			return fmt.Errorf("Line %d: Missing semicolon", element.LineNumber)
+		}
+
+		filename := strings.TrimSuffix(strings.TrimPrefix(element.Text, "@config"), ";")
+		filename = strings.Trim(strings.TrimSpace(filename), `"'`)
+		if filename == "" {
+			return fmt.Errorf("Line %d: Missing config filename", element.LineNumber)
+		}
+
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(filepath.Dir(srcFilename), filename)
+		}
+
+		err := p.Theme.Load(filename)
+		if err != nil {
+			return fmt.Errorf("Line %d: %v", element.LineNumber, err)
+		}
+	}
+
+	return nil
+}
+
+const variantSyntax = "expected '@variant name (&:hover, &:focus);' or '@variant name (@media print);'"
+
+// ReadVariants reads the custom variants defined with
+// "@variant hocus (&:hover, &:focus);" or "@variant print (@media print);".
+func (p *parser) ReadVariants(elements *elements) error {
+	for _, element := range elements.Items {
+		if element.ElementType != typeVariant {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(element.Text, "@variant"), ";"))
+		name, variant, _ := strings.Cut(text, " ")
+
+		// The semicolon is optional after the parentheses, as in
+		// "@variant hocus (&:hover, &:focus)".
+		variant = strings.TrimSpace(variant)
+		parenthesized := strings.HasPrefix(variant, "(") && strings.HasSuffix(variant, ")")
+		if parenthesized {
+			variant = strings.TrimSpace(variant[1 : len(variant)-1])
+		}
+
+		if !parenthesized && !endsWithSemiColon(element.Text) {
+			return fmt.Errorf("Line %d: Missing semicolon, %s", element.LineNumber, variantSyntax)
+		}
+
+		if name == "" || variant == "" {
+			return fmt.Errorf("Line %d: Invalid variant, %s", element.LineNumber, variantSyntax)
+		}
+
+		if !strings.Contains(variant, "&") && !strings.HasPrefix(variant, "@") {
+			return fmt.Errorf("Line %d: Variant '%s' needs a selector with & or an at-rule, %s", element.LineNumber, name, variantSyntax)
+		}
+
+		p.Variants[name] = variant
+	}
+
+	return nil
+}
+
+// applyToMixins converts "@apply px-4 hover:bg-blue-700;" into the mixin
+// calls ".px-4; .hover:bg-blue-700;". A trailing !important makes all of
+// them important.
+func applyToMixins(str string, separator string) (string, error) {
+	if !endsWithSemiColon(str) {
+		return "", fmt.Errorf("Missing semicolon")
+	}
+
+	utilities := strings.Fields(strings.TrimSuffix(strings.TrimPrefix(str, "@apply"), ";"))
+
+	important := false
+	if len(utilities) > 0 && utilities[len(utilities)-1] == "!important" {
+		important = true
+		utilities = utilities[:len(utilities)-1]
+	}
+
+	if len(utilities) == 0 {
+		return "", fmt.Errorf("Missing utilities")
+	}
+
+	mixins := make([]string, 0)
+	for _, utility := range utilities {
+		if important {
+			variants, base := splitVariants(utility, separator)
+			utility = strings.Join(append(variants, "!"+base), separator)
+		}
+
+		mixins = append(mixins, "."+utility+";")
+	}
+
+	return strings.Join(mixins, " "), nil
+}
+
 func isVariable(str string) bool {
 	if str[0:1] != "@" {
 		return false
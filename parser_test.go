@@ -0,0 +1,64 @@
+package tailless
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseString parses a stylesheet and returns the css.
+func parseString(t *testing.T, less string, options Options) (string, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "style.less")
+	dest := filepath.Join(dir, "style.css")
+
+	err := os.WriteFile(src, []byte(less), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ParseWithOptions(src, dest, options)
+	if err != nil {
+		return "", err
+	}
+
+	css, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(css), nil
+}
+
+func TestNestedTailwindDirective(t *testing.T) {
+	less := ".m\n{\n    @tailwind utilities;\n}\n.a\n{\n    .m;\n}\n"
+
+	_, err := parseString(t, less, Options{})
+	if err == nil || err.Error() != "Line 3: @tailwind is only allowed at the top level" {
+		t.Errorf("expected an error for the nested directive, got %v", err)
+	}
+}
+
+func TestVariantSyntax(t *testing.T) {
+	less := "@variant hocus (&:hover, &:focus)\n\n.a\n{\n    .hocus:underline;\n}\n"
+
+	css, err := parseString(t, less, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ".a:hover,\n.a:focus {\n  text-decoration: underline;\n}\n"
+	if css != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, css)
+	}
+
+	less = "@variant hocus\n{\n    &:hover, &:focus;\n}\n"
+
+	_, err = parseString(t, less, Options{})
+	if err == nil || !strings.Contains(err.Error(), "expected '@variant name (&:hover, &:focus);'") {
+		t.Errorf("expected an error with the variant syntax, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package tailless
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// The Tailwind preflight styles, with the font families and the colors
+// taken from the theme, and the defaults of the custom properties the
+// utilities compose with.
+//
+//go:embed preflight.css
+var preflightData string
+
+// newTailwindNode creates the node of a @tailwind directive. "@tailwind
+// base;" renders the preflight styles, "@tailwind utilities;" is where the
+// utility classes found in the templates are placed.
+func (p *parser) newTailwindNode(text string, lineNumber int) (*tailwindNode, error) {
+	if !endsWithSemiColon(text) {
+		return nil, fmt.Errorf("Line %d: Missing semicolon", lineNumber)
+	}
+
+	directive := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "@tailwind"), ";"))
+
+	switch directive {
+	case "base":
+		css, err := p.Theme.ReplaceFunctions(preflightData)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", lineNumber, err)
+		}
+
+		return newTailwindNode(directive, css, lineNumber), nil
+	case "components", "utilities":
+		return newTailwindNode(directive, "", lineNumber), nil
+	}
+
+	return nil, fmt.Errorf("Line %d: Unknown directive '@tailwind %s'", lineNumber, directive)
+}
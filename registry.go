@@ -0,0 +1,129 @@
+package tailless
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Declaration is a CSS declaration of a utility registered from Go.
+type Declaration struct {
+	Property string
+	Value    string
+}
+
+// UtilityFunc returns the declarations of a utility for the value that
+// matches the "*" of its pattern, or nil when the value isn't supported.
+type UtilityFunc func(value string) []Declaration
+
+// VariantFunc returns the selector of a variant for a selector of the rule
+// that uses it, like selector + ":hover", or an at-rule like "@media print"
+// that wraps the declarations. It is called for every selector of the rule.
+type VariantFunc func(selector string) string
+
+type registeredUtility struct {
+	Pattern  string
+	Function UtilityFunc
+}
+
+var registry = struct {
+	sync.Mutex
+	Utilities []registeredUtility
+	Static    stringMap
+	Variants  map[string]VariantFunc
+}{Static: make(stringMap), Variants: make(map[string]VariantFunc)}
+
+// RegisterUtility adds a utility to every parser. The "*" in the pattern,
+// like "elevation-*", matches the value that is passed to the function.
+// Registered utilities take precedence over the built-in utilities, and are
+// usually registered from an init function:
+//
+//	tailless.RegisterUtility("elevation-*", func(value string) []tailless.Declaration {
+//	    level, err := strconv.Atoi(value)
+//	    if err != nil {
+//	        return nil
+//	    }
+//	    return []tailless.Declaration{{"box-shadow", elevations[level]}, {"z-index", value}}
+//	})
+func RegisterUtility(pattern string, function UtilityFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.Utilities = append(registry.Utilities, registeredUtility{pattern, function})
+}
+
+// RegisterVariant adds a variant to every parser, like "hocus" for
+// hocus:bg-red-500:
+//
+//	tailless.RegisterVariant("hocus", func(selector string) string {
+//	    return selector + ":hover, " + selector + ":focus"
+//	})
+func RegisterVariant(name string, function VariantFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.Variants[name] = function
+}
+
+// AddUtility adds a utility with fixed declarations to every parser, like
+// AddUtility("brand-heading", "font-family: Inter; font-weight: 700;").
+func AddUtility(name string, css string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.Static[name] = css
+}
+
+func initRegistry(c *tailwindCollection) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for name, css := range registry.Static {
+		c.Add("."+name, css)
+	}
+
+	// A registered variant replaces a built-in variant with the same name.
+	for name, function := range registry.Variants {
+		delete(c.Variants, name)
+		c.VariantFunctions[name] = function
+	}
+
+	// Longer patterns are more specific, like "elevation-inner-*" before
+	// "elevation-*".
+	c.Functions = append(c.Functions, registry.Utilities...)
+	sort.SliceStable(c.Functions, func(i, j int) bool {
+		return len(c.Functions[i].Pattern) > len(c.Functions[j].Pattern)
+	})
+}
+
+// getRegistered resolves a utility registered with RegisterUtility.
+func (t *tailwindCollection) getRegistered(name string) *utility {
+	for _, r := range t.Functions {
+		before, after, wildcard := strings.Cut(r.Pattern, "*")
+
+		value := ""
+		if wildcard {
+			if !strings.HasPrefix(name, before) || !strings.HasSuffix(name, after) || len(name) <= len(before)+len(after) {
+				continue
+			}
+
+			value = name[len(before) : len(name)-len(after)]
+		} else if name != r.Pattern {
+			continue
+		}
+
+		declarations := r.Function(value)
+		if declarations == nil {
+			continue
+		}
+
+		text := ""
+		for _, d := range declarations {
+			text += d.Property + ": " + d.Value + "; "
+		}
+
+		return &utility{Declarations: strings.TrimSpace(text)}
+	}
+
+	return nil
+}
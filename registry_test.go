@@ -0,0 +1,77 @@
+package tailless
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// resetRegistry restores the registry when the test ends, so the utilities
+// and variants it registers don't leak into other tests.
+func resetRegistry(t *testing.T) {
+	t.Helper()
+
+	registry.Lock()
+	utilities := slices.Clone(registry.Utilities)
+	static := maps.Clone(registry.Static)
+	variants := maps.Clone(registry.Variants)
+	registry.Unlock()
+
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+
+		registry.Utilities = utilities
+		registry.Static = static
+		registry.Variants = variants
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	resetRegistry(t)
+
+	RegisterUtility("counter-*", func(value string) []Declaration {
+		return []Declaration{{"content", `"$` + value + `"`}}
+	})
+	RegisterVariant("hocus", func(selector string) string {
+		return selector + ":hover, " + selector + ":focus"
+	})
+	RegisterVariant("landscape", func(selector string) string {
+		return "@media (orientation: landscape)"
+	})
+	AddUtility("price", `content: "$"; font-weight: 700;`)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{".counter-5", ".x {\n  content: \"$5\";\n}\n"},
+		{".price", ".x {\n  content: \"$\";\n  font-weight: 700;\n}\n"},
+		{".hocus:price", ".x:hover,\n.x:focus {\n  content: \"$\";\n  font-weight: 700;\n}\n"},
+		{".md:hocus:underline", "@media (min-width: 768px) {\n.x:hover,\n.x:focus {\n  text-decoration: underline;\n}\n}\n"},
+		{".landscape:underline", "@media (orientation: landscape) {\n.x {\n  text-decoration: underline;\n}\n}\n"},
+	}
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := renderUtility(t, tailwind, test.name)
+			if actual != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRegistryReset(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		resetRegistry(t)
+		AddUtility("price", "font-weight: 700;")
+	})
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+	if tailwind.Get(".price") != nil {
+		t.Errorf("expected the registered utility to be removed")
+	}
+}
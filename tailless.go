@@ -1,6 +1,48 @@
 package tailless
 
+type Options struct {
+	// ThemeFile is the path of a JSON theme file that extends or overrides
+	// the default Tailwind theme.
+	ThemeFile string
+
+	// Content are globs of the templates that are scanned for Tailwind
+	// classes, like "templates/**/*.html". A rule is generated for every
+	// utility class that is used.
+	Content []string
+
+	// CustomProperties emits the theme as custom properties in a :root rule,
+	// like --color-emerald-700 and --spacing-4, and makes the utilities refer
+	// to them. Overriding the properties, for example under a class, changes
+	// the theme at runtime.
+	CustomProperties bool
+
+	// Palette selects the default colors: "v3" (the default) is the Tailwind
+	// v3 hex palette, "v4" the Tailwind v4 OKLCH palette.
+	Palette string
+
+	// ColorFormat converts the theme colors to "hex", "rgb" or "oklch".
+	// Colors outside the sRGB gamut are gamut mapped for hex and rgb. OKLCH
+	// output gets hex fallback declarations for older browsers.
+	ColorFormat string
+
+	// Prefix namespaces the utilities, so with "tw-" .bg-red-500 is used as
+	// .tw-bg-red-500 and md:tw-bg-red-500, both as mixins and in templates.
+	Prefix string
+
+	// Separator separates the variants from the utility, ":" by default.
+	Separator string
+
+	// LogicalFallbacks adds the physical properties of logical utilities,
+	// like margin-left for ms-4, under [dir="ltr"] and mirrored under
+	// [dir="rtl"], for browsers without logical properties.
+	LogicalFallbacks bool
+}
+
 func Parse(srcFilename, destFilename string) error {
-	parser := newParser()
+	return ParseWithOptions(srcFilename, destFilename, Options{})
+}
+
+func ParseWithOptions(srcFilename, destFilename string, options Options) error {
+	parser := newParser(options)
 	return parser.Parse(srcFilename, destFilename)
 }
@@ -1,952 +1,752 @@
 package tailless
 
 import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
 type stringMap map[string]string
 
-type tailwindCollection struct {
-	Items stringMap
+var reProperty = regexp.MustCompile(`^(--)?[a-zA-Z][a-zA-Z0-9-]*$`)
+var reCustomProperty = regexp.MustCompile(`--tw-[a-z-]+`)
+
+// The utility catalog. Every utility has a name pattern in which "*" is
+// replaced by the keys of its value scales, a declarations template in
+// which $1, $2, ... are replaced by the scale values, optionally a nested
+// selector like "& > :not([hidden]) ~ :not([hidden])" for utilities that
+// style child elements, optionally the @keyframes it animates, optionally a
+// responsive template that is repeated for every screen of the theme inside
+// a min-width media query, and the modifiers it supports:
+//
+//	negative  also generates "-name" with the negated value
+//	opacity   accepts a "/50" suffix that applies an opacity to the color
+//
+// The value scales are either defined in the catalog itself or taken from
+// the theme (colors, spacing, fontSize, borderRadius, boxShadow, screens
+// and fontFamily).
+//
+// Utilities can be prefixed with variants, like .md:hover:bg-red-500. A
+// variant is either a selector in which & is the calling selector, or an
+// at-rule that wraps the declarations. Every screen of the theme is a
+// min-width media query variant, and every container size, like @md, a
+// container query variant.
+//
+//go:embed tailwind.json
+var tailwindData []byte
+
+var catalog = loadCatalog()
+
+type utilityCatalog struct {
+	Scales     map[string]stringMap  `json:"scales"`
+	Keyframes  map[string][]keyframe `json:"keyframes"`
+	Properties []string              `json:"properties"`
+	Variants   stringMap             `json:"variants"`
+	Utilities  []utilityDefinition   `json:"utilities"`
+}
+
+type keyframe struct {
+	Selector     string `json:"selector"`
+	Declarations string `json:"declarations"`
+}
+
+type utilityDefinition struct {
+	Pattern    string   `json:"pattern"`
+	Template   string   `json:"template"`
+	Selector   string   `json:"selector"`
+	Keyframes  string   `json:"keyframes"`
+	Responsive string   `json:"responsive"`
+	Values     []string `json:"values"`
+	Modifiers  []string `json:"modifiers"`
+}
+
+func loadCatalog() *utilityCatalog {
+	catalog := utilityCatalog{}
+
+	err := json.Unmarshal(tailwindData, &catalog)
+	if err != nil {
+		panic(fmt.Sprintf("tailwind.json: %v", err))
+	}
+
+	return &catalog
+}
+
+type utility struct {
+	Template   string
+	Selector   string
+	Keyframes  string
+	Responsive string
+	Values     []string
+	Modifiers  []string
+
+	// Declarations are rendered declarations that are used as is, instead
+	// of the template, so a "$" in a value isn't taken for a placeholder.
+	Declarations string
+}
+
+// Text returns the declarations of the utility. Declarations that refer to a
+// value the utility doesn't have, like the line height of a font size that
+// only defines the size, are left out.
+func (u *utility) Text() string {
+	if u.Declarations != "" {
+		return u.Declarations
+	}
+
+	text := ""
+
+	for _, declaration := range splitDeclarations(u.Template) {
+		for i, value := range u.Values {
+			declaration = strings.ReplaceAll(declaration, "$"+strconv.Itoa(i+1), value)
+		}
+
+		if strings.Contains(declaration, "$") {
+			continue
+		}
+
+		if text != "" {
+			text += " "
+		}
+
+		text += declaration
+	}
+
+	return text
 }
 
-func newTailwindCollection() *tailwindCollection {
-	collection := tailwindCollection{}
-	collection.Items = make(map[string]string)
+func (u *utility) HasModifier(modifier string) bool {
+	return slices.Contains(u.Modifiers, modifier)
+}
+
+type tailwindCollection struct {
+	Items      map[string]*utility
+	Variants   stringMap
+	Theme      *theme
+	Keyframes  []string
+	Properties []string
+	Prefix     string
+	Separator  string
+	Functions  []registeredUtility
+
+	// VariantFunctions are the variants registered with RegisterVariant.
+	VariantFunctions map[string]VariantFunc
+	Rules            map[string]*selectorNode
+
+	// LogicalFallbacks adds the physical properties of logical utilities
+	// for [dir="ltr"] and [dir="rtl"].
+	LogicalFallbacks bool
+}
+
+func newTailwindCollection(theme *theme, prefix string, separator string) *tailwindCollection {
+	collection := tailwindCollection{Theme: theme, Prefix: prefix, Separator: separator}
+	collection.Items = make(map[string]*utility)
+	collection.Variants = make(stringMap)
+	collection.Rules = make(map[string]*selectorNode)
+	collection.VariantFunctions = make(map[string]VariantFunc)
 
 	initTailwind(&collection)
+	initVariants(&collection)
+	initRegistry(&collection)
 
 	return &collection
 }
 
 func (t *tailwindCollection) Get(name string) *selectorNode {
-	value := t.Items[name]
-	if value == "" {
+	if !strings.HasPrefix(name, ".") {
+		return nil
+	}
+
+	variants, base := splitVariants(strings.ReplaceAll(name[1:], `\`, ""), t.Separator)
+
+	important := false
+	if strings.HasPrefix(base, "!") {
+		important = true
+		base = base[1:]
+	} else if strings.HasSuffix(base, "!") {
+		important = true
+		base = base[:len(base)-1]
+	}
+
+	base, ok := t.removePrefix(base)
+	if !ok {
+		return nil
+	}
+
+	direction := ""
+	for _, variant := range variants {
+		if variant == "ltr" || variant == "rtl" {
+			direction = variant
+		}
+	}
+
+	n := t.getUtility("."+base, important, direction)
+	if n == nil {
+		return nil
+	}
+
+	for i := len(variants) - 1; i >= 0; i-- {
+		variant, ok := t.getVariant(variants[i])
+		if ok {
+			n = wrapVariant(n, variant)
+			continue
+		}
+
+		function := t.VariantFunctions[variants[i]]
+		if function == nil {
+			return nil
+		}
+
+		wrapper := newVariantNode(function, 0)
+		wrapper.SetChildren(n.Children)
+
+		n = &selectorNode{}
+		n.Children = []node{wrapper}
+	}
+
+	return n
+}
+
+// removePrefix removes the class name prefix from a utility, which for
+// negative utilities follows the minus sign, like -tw-mt-2.
+func (t *tailwindCollection) removePrefix(name string) (string, bool) {
+	if t.Prefix == "" {
+		return name, true
+	}
+
+	if strings.HasPrefix(name, "-"+t.Prefix) {
+		return "-" + name[len(t.Prefix)+1:], true
+	}
+
+	if strings.HasPrefix(name, t.Prefix) {
+		return name[len(t.Prefix):], true
+	}
+
+	return "", false
+}
+
+func (t *tailwindCollection) getUtility(name string, important bool, direction string) *selectorNode {
+	rule := t.Rules[name]
+	if rule != nil {
+		n := rule.GetCopy().(*selectorNode)
+		if important {
+			makeImportant(n)
+		}
+
+		return n
+	}
+
+	u := t.getRegistered(name[1:])
+	if u == nil {
+		u = t.Items[name]
+	}
+
+	if u == nil {
+		u = t.getWithOpacity(name)
+	}
+
+	if u == nil {
+		u = arbitraryProperty(name)
+	}
+
+	if u == nil {
 		return nil
 	}
 
-	children := make([]node, 0)
-	child := newDeclarationNode(value, 0)
-	children = append(children, child)
+	if u.Keyframes != "" && !slices.Contains(t.Keyframes, u.Keyframes) {
+		t.Keyframes = append(t.Keyframes, u.Keyframes)
+	}
+
+	text := u.Text()
+
+	for _, property := range reCustomProperty.FindAllString(text, -1) {
+		if slices.Contains(catalog.Properties, property) && !slices.Contains(t.Properties, property) {
+			t.Properties = append(t.Properties, property)
+		}
+	}
+
+	declarations := newDeclarationNodes(text, important)
+	if t.LogicalFallbacks {
+		declarations = append(physicalFallbacks(declarations, direction), declarations...)
+	}
 
 	n := selectorNode{}
-	n.Children = children
+	n.Children = declarations
+
+	if u.Selector != "" {
+		rule := newSelectorNode([]string{u.Selector}, 0)
+		rule.Children = declarations
+		n.Children = []node{rule}
+	}
+
+	if u.Responsive != "" {
+		for _, screen := range t.Theme.SortedScreens() {
+			value := t.Theme.Screens[screen]
+
+			media := newAtRuleNode("@media (min-width: "+value+")", 0)
+			media.Children = newDeclarationNodes(strings.ReplaceAll(u.Responsive, "$1", value), important)
+
+			n.AddChild(media)
+		}
+	}
 
 	return &n
 }
 
-func (t *tailwindCollection) Set(name string, node *selectorNode) {
+func makeImportant(n node) {
+	for _, child := range n.GetChildren() {
+		declaration, ok := child.(*declarationNode)
+		if ok && !strings.HasSuffix(declaration.Text, "!important;") {
+			declaration.Text = strings.TrimSuffix(declaration.Text, ";") + " !important;"
+		}
+
+		makeImportant(child)
+	}
+}
+
+func newDeclarationNodes(text string, important bool) []node {
+	nodes := make([]node, 0)
+
+	for _, d := range splitDeclarations(text) {
+		if important {
+			d = strings.TrimSuffix(d, ";") + " !important;"
+		}
+
+		nodes = append(nodes, newDeclarationNode(d, 0))
+	}
+
+	return nodes
+}
+
+// getWithOpacity resolves color utilities with an opacity modifier, like
+// .bg-red-500/50.
+func (t *tailwindCollection) getWithOpacity(name string) *utility {
+	pos := strings.LastIndex(name, "/")
+	if pos < 0 {
+		return nil
+	}
+
+	u := t.Items[name[:pos]]
+	if u == nil || !u.HasModifier("opacity") {
+		return nil
+	}
+
+	opacity := catalog.Scales["opacity"][name[pos+1:]]
+	if opacity == "" {
+		return nil
+	}
+
+	modified := *u
+	modified.Values = slices.Clone(u.Values)
+	modified.Values[0] = colorWithOpacity(u.Values[0], opacity)
+
+	return &modified
+}
+
+// GetKeyframes returns the @keyframes rules of the animations that were
+// used, to be added once at the top level of the output.
+func (t *tailwindCollection) GetKeyframes() []node {
+	nodes := make([]node, 0)
+
+	for _, name := range t.Keyframes {
+		atRule := newAtRuleNode("@keyframes "+name, 0)
+
+		for _, frame := range catalog.Keyframes[name] {
+			rule := newSelectorNode(appendSelectors(nil, frame.Selector), 0)
+			for _, d := range splitDeclarations(frame.Declarations) {
+				rule.AddChild(newDeclarationNode(d, 0))
+			}
+
+			atRule.AddChild(rule)
+		}
+
+		nodes = append(nodes, atRule)
+	}
+
+	return nodes
+}
+
+// addThemeProperties adds the :root rule with the custom properties of the
+// theme at the top of the output, after the imports.
+func addThemeProperties(tree *rootNode, theme *theme) {
+	rule := newSelectorNode([]string{":root"}, 0)
+	for _, d := range theme.CustomPropertyDeclarations() {
+		rule.AddChild(newDeclarationNode(d, 0))
+	}
+
+	position := 0
+	for position < len(tree.Children) {
+		_, ok := tree.Children[position].(*importNode)
+		if !ok {
+			break
+		}
+
+		position++
+	}
+
+	tree.Children = slices.Insert(tree.Children, position, node(rule))
+}
+
+// GetProperties returns the @property rules of the custom properties the
+// used utilities compose through. They don't inherit, so a utility like
+// .shadow doesn't pick up the ring of an ancestor.
+func (t *tailwindCollection) GetProperties() []node {
+	nodes := make([]node, 0)
+
+	for _, property := range t.Properties {
+		atRule := newAtRuleNode("@property "+property, 0)
+		atRule.AddChild(newDeclarationNode(`syntax: "*";`, 0))
+		atRule.AddChild(newDeclarationNode("inherits: false;", 0))
+
+		nodes = append(nodes, atRule)
+	}
 
+	return nodes
 }
 
-func (t *tailwindCollection) Add(name string, value string) {
-	t.Items[name] = value
+// Set adds a utility with the declarations and nested rules of a node.
+func (t *tailwindCollection) Set(name string, node *selectorNode) {
+	t.Rules[name] = node
+}
+
+// Add adds a utility with fixed declarations.
+func (t *tailwindCollection) Add(name string, declarations string) {
+	t.Items[name] = &utility{Declarations: strings.TrimSpace(declarations)}
 }
 
 func initTailwind(c *tailwindCollection) {
-	initColors(c, "text", "color: $1;")
-	initColors(c, "bg", "background-color: $1;")
-
-	initSizes(c, "p", "padding: $1;")
-	initSizes(c, "px", "padding-left: $1; padding-right: $1;")
-	initSizes(c, "py", "padding-top: $1; padding-bottom: $1;")
-	initSizes(c, "pl", "padding-left: $1;")
-	initSizes(c, "pt", "padding-top: $1;")
-	initSizes(c, "pr", "padding-right: $1;")
-	initSizes(c, "pb", "padding-bottom: $1;")
-
-	initSizes(c, "m", "margin: $1")
-	initSizes(c, "mx", "margin-left: $1; margin-right: $1;")
-	c.Add(".mx-auto", "margin-left: auto; margin-right: auto;")
-	initSizes(c, "my", "margin-top: $1; margin-bottom: $1;")
-	initSizes(c, "ml", "margin-left: $1;")
-	initSizes(c, "mt", "margin-top: $1;")
-	initSizes(c, "mr", "margin-right: $1;")
-	initSizes(c, "mb", "margin-bottom: $1;")
-
-	initSizes(c, "inset", "inset: $1;")
-	initSizes(c, "inset-x", "left: $1; right: $1;")
-	initSizes(c, "inset-y", "top: $1; bottom: $1;")
-	initSizes(c, "top", "top: $1;")
-	initSizes(c, "right", "right: $1;")
-	initSizes(c, "bottom", "bottom: $1;")
-	initSizes(c, "left", "left: $1;")
-	initSizes(c, "gap", "gap: $1;")
-
-	initWidthHeight(c, "w", "width: $1;")
-	initWidthHeight(c, "h", "height: $1;")
-	initWidth(c, "w", "width: $1;")
-	c.Add(".h-screen", "height: 100vh;")
-	initMinWidth(c, "min-w", "min-width: $1;")
-	initMaxWidth(c, "max-w", "max-width: $1;")
-	initMinHeight(c, "min-h", "min-height: $1;")
-	initMaxHeight(c, "max-h", "max-height: $1;")
-
-	initFontSizes(c, "text", "font-size: $1; line-height: $2;")
-	initLeading(c, "leading", "line-height: $1;")
-	initColors(c, "text", "color: $1;")
-	initTextAlign(c, "text", "text-align: $1;")
-
-	initFontWeights(c, "font", "font-weight: $1;")
-
-	initBorderRadius(c, "rounded", "border-radius: $1;")
-	initBorderRadius(c, "rounded-t", "border-top-left-radius: $1; border-top-right-radius: $1;")
-	initBorderRadius(c, "rounded-r", "border-top-right-radius: $1; border-bottom-right-radius: $1;")
-	initBorderRadius(c, "rounded-b", "border-bottom-left-radius: $1; border-bottom-right-radius: $1;")
-	initBorderRadius(c, "rounded-l", "border-top-left-radius: $1; border-bottom-left-radius: $1;")
-
-	initBorderWidth(c, "border", "border-width: $1;")
-	initBorderWidth(c, "border-l", "border-left-width: $1;")
-	initBorderWidth(c, "border-t", "border-top-width: $1;")
-	initBorderWidth(c, "border-r", "border-right-width: $1;")
-	initBorderWidth(c, "border-b", "border-bottom-width: $1;")
-	initColors(c, "border", "border-color: $1;")
-	initBorderStyle(c, "border", "border-style: $1;")
-
-	initOutlineWidth(c, "outline", "outline-width: $1;")
-	initColors(c, "outline", "outline-color: $1;")
-	initOutlineStyle(c, "outline", "outline-style: $1;")
-	initOutlineWidth(c, "outline-offset", "outline-offset: $1;")
-	c.Add(".outline-none", "outline: none;")
-
-	c.Add(".underline", "text-decoration: underline;")
-	c.Add(".overline", "text-decoration: overline;")
-	c.Add(".line-through", "text-decoration: linie-through;")
-	c.Add(".no-underline", "text-decoration: none;")
-	initColors(c, "decoration", "text-decoration-color: $1;")
-	initOverflow(c, "overflow", "overflow: $1;")
-	initOverflow(c, "overflow-x", "overflow-x: $1;")
-	initOverflow(c, "overflow-y", "overflow-y: $1;")
-
-	initObjectFit(c, "object", "object-fit: $1;")
-	initObjectPosition(c, "object", "object-position: $1;")
-	initOpacity(c, "opacity", "opacity: $1;")
-
-	initLetterSpacing(c, "tracking", "letter-spacing: $1;")
-
-	c.Add(".truncate", "overflow: hidden; text-overflow: ellipsis; white-space: nowrap;")
-	c.Add(".uppercase", "text-transform: uppercase;")
-	c.Add(".text-ellipsis", "text-overflow: ellipsis;")
-	c.Add(".text-clip", "text-overflow: clip;")
-
-	c.Add(".block", "display: block;")
-	c.Add(".inline-block", "display: inline-block;")
-	c.Add(".inline", "display: inline;")
-	c.Add(".flex", "display: flex;")
-	c.Add(".inline-flex", "display: inline-flex;")
-	c.Add(".grid", "display: grid")
-	c.Add(".inline-grid", "display: inline-grid")
-	c.Add(".contents", "display: contents")
-	c.Add(".list-item", "display: list-item")
-	c.Add(".hidden", "display: none;")
-
-	c.Add(".static", "position: static;")
-	c.Add(".fixed", "position: fixed;")
-	c.Add(".absolute", "position: absolute;")
-	c.Add(".relative", "position: relative;")
-	c.Add(".sticky", "position: sticky;")
-
-	c.Add(".float-left", "float: left;")
-	c.Add(".float-right", "float: right;")
-	c.Add(".float-none", "float: none;")
-
-	c.Add(".flex-row", "flex-direction: row;")
-	c.Add(".flex-row-reverse", "flex-direction: row-reverse;")
-	c.Add(".flex-col", "flex-direction: column;")
-	c.Add(".flex-col-reverse", "flex-direction: column-reverse;")
-	initSizes(c, "basis", "flex-basis: $1;")
-	c.Add(".flex-wrap", "flex-wrap: wrap;")
-	c.Add(".flex-wrap-reverse", "flex-wrap: wrap-reverse;")
-	c.Add(".flex-nowrap", "flex-wrap: nowrap;")
-	c.Add(".flex-1", "flex: 1 1 0%;")
-	c.Add(".flex-auto", "flex: 1 1 auto;")
-	c.Add(".flex-initial", "flex: 0 1 auto;")
-	c.Add(".flex-none", "flex: none;")
-	c.Add(".grow", "flex-grow: 1;")
-	c.Add(".grow-0", "flex-grow: 0;")
-	c.Add(".shrink", "flex-shrink: 1;")
-	c.Add(".shrink-0", "flex-shrink: 0;")
-	c.Add(".flex-grow", "flex-grow: 1;")
-	c.Add(".flex-grow-0", "flex-grow: 0;")
-	c.Add(".flex-shrink", "flex-shrink: 1;")
-	c.Add(".flex-shrink-0", "flex-shrink: 0;")
-
-	c.Add(".text-wrap", "text-wrap: wrap;")
-	c.Add(".text-nowrap", "text-wrap: nowrap;")
-	c.Add(".text-balance", "text-wrap: balance;")
-	c.Add(".text-pretty", "text-wrap: pretty;")
-
-	initJustifyContent(c, "justify", "justify-content: $1;")
-	initJustifyItems(c, "justify-items", "justify-items: $1;")
-	initJustifySelf(c, "justify-self", "justify-self: $1;")
-	initAlignContent(c, "content", "align-content: $1;")
-	initAlignItems(c, "items", "align-items: $1;")
-	initAlignSelf(c, "self", "align-self: $1;")
-	initSizes(c, "gap", "gap: $1;")
-	initSizes(c, "gap-x", "column-gap: $1;")
-	initSizes(c, "gap-y", "row-gap: $1;")
-	initZIndex(c, "z", "z-index: $1;")
-	initShadow(c, "shadow", "box-shadow: $1;")
-
-	c.Add(".transition-none", "transition-property: none;")
-	c.Add(".transition-all", "transition-property: all; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms;")
-	c.Add(".transition", "transition-property: color, background-color, border-color, text-decoration-color, fill, stroke, opacity, box-shadow, transform, filter, backdrop-filter; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms;")
-	c.Add(".transition-colors", "transition-property: color, background-color, border-color, text-decoration-color, fill, stroke; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms;")
-	c.Add(".transition-opacity", "transition-property: opacity; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms; ")
-	c.Add(".transition-shadow", "transition-property: box-shadow; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms;")
-	c.Add(".transition-transform", "transition-property: transform; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 150ms;")
-
-	initDurationDelay(c, "duration", "transition-duration: $1;")
-	initEase(c, "ease", "transition-timing-function: $1;")
-	initDurationDelay(c, "delay", "transition-delay: $1;")
-
-	initScale(c, "scale", "transform: scale($1);")
-	initScale(c, "scale-x", "transform: scale-X($1);")
-	initScale(c, "scale-y", "transform: scale-Y($1);")
-	initRotate(c, "rotate", "transform: rotate($1);")
-	initTranslate(c, "translate-x", "transform: translateX($1);")
-	initTranslate(c, "translate-y", "transform: translateY($1);")
-	initTranslate(c, "-translate-x", "transform: translateX(-$1);")
-	initTranslate(c, "-translate-y", "transform: translateY(-$1);")
-	initSkew(c, "skew-x", "transform: skewX($1);")
-	initSkew(c, "skew-y", "transform: skewY($1);")
-	initOrigin(c, "origin", "transform-origin: $1;")
-
-	initCursors(c, "cursor", "cursor: $1;")
-	initPointerEvents(c, "pointer-events", "pointer-events: $1;")
-}
-
-func initSizes(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("px", "1px")
-	s.Set("0.5", "0.125rem")
-	s.Set("1", "0.25rem")
-	s.Set("1.5", "0.375rem")
-	s.Set("2", "0.5rem")
-	s.Set("2.5", "0.625rem")
-	s.Set("3", "0.75rem")
-	s.Set("3.5", "0.875rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("88", "22rem")
-	s.Set("96", "24rem")
-}
-
-func initColors(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("inherit", "inherit")
-	s.Set("current", "currentColor")
-	s.Set("transparent", "transparent")
-	s.Set("black", "#000000")
-	s.Set("white", "#ffffff")
-
-	for name, value := range *colors {
-		s.Set(name, value)
-	}
-}
-
-func initWidthHeight(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("px", "1px")
-	s.Set("0.5", "0.125rem")
-	s.Set("1", "0.25rem")
-	s.Set("1.5", "0.375rem")
-	s.Set("2", "0.5rem")
-	s.Set("2.5", "0.625rem")
-	s.Set("3", "0.75rem")
-	s.Set("3.5", "0.875rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("96", "24rem")
-	s.Set("auto", "auto")
-	s.Set("1/2", "50%")
-	s.Set("1/3", "33.333333%")
-	s.Set("2/3", "66.666667%")
-	s.Set("1/4", "25%")
-	s.Set("2/4", "50%")
-	s.Set("3/4", "75%")
-	s.Set("1/5", "20%")
-	s.Set("2/5", "40%")
-	s.Set("3/5", "60%")
-	s.Set("4/5", "80%")
-	s.Set("1/6", "16.666667%")
-	s.Set("2/6", "33.333333%")
-	s.Set("3/6", "50%")
-	s.Set("4/6", "66.666667%")
-	s.Set("5/6", "83.333333%")
-	s.Set("full", "100%")
-	s.Set("min", "min-content")
-	s.Set("max", "max-content")
-	s.Set("fit", "fit-content")
-}
-
-func initWidth(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("1/12", "8.333333%")
-	s.Set("2/12", "16.666667%")
-	s.Set("3/12", "25%")
-	s.Set("4/12", "33.333333%")
-	s.Set("5/12", "41.666667%")
-	s.Set("6/12", "50%")
-	s.Set("7/12", "58.333333%")
-	s.Set("8/12", "66.666667%")
-	s.Set("9/12", "75%")
-	s.Set("10/12", "83.333333%")
-	s.Set("11/12", "91.666667%")
-	s.Set("screen", "100vw")
-}
-
-func initMinWidth(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("1", "0.25rem")
-	s.Set("2", "0.5rem")
-	s.Set("3", "0.75rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("96", "24rem")
-
-	s.Set("px", "1px")
-	s.Set("0.5", "0.125rem")
-	s.Set("1.5", "0.375rem")
-	s.Set("2.5", "0.625rem")
-	s.Set("3.5", "0.875rem")
-
-	s.Set("full", "100%")
-	s.Set("min", "min-content")
-	s.Set("max", "max-content")
-	s.Set("fit", "fit-content")
-}
-
-func initMaxWidth(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0rem")
-	s.Set("none", "none")
-	s.Set("xs", "20rem")
-	s.Set("sm", "24rem")
-	s.Set("md", "28rem")
-	s.Set("lg", "32rem")
-	s.Set("xl", "36rem")
-	s.Set("2xl", "42rem")
-	s.Set("3xl", "48rem")
-	s.Set("4xl", "56rem")
-	s.Set("5xl", "64rem")
-	s.Set("6xl", "72rem")
-	s.Set("7xl", "80rem")
-	s.Set("full", "100%")
-	s.Set("min", "min-content")
-	s.Set("max", "max-content")
-	s.Set("fit", "fit-content")
-	s.Set("prose", "65ch")
-	s.Set("screen-sm", "640px")
-	s.Set("screen-md", "768px")
-	s.Set("screen-lg", "1024px")
-	s.Set("screen-xl", "1280px")
-	s.Set("screen-2xl", "1536px")
-}
-
-func initMinHeight(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("1", "0.25rem")
-	s.Set("2", "0.5rem")
-	s.Set("3", "0.75rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("96", "24rem")
-	s.Set("full", "100%")
-	s.Set("screen", "100vh")
-	s.Set("min", "min-content")
-	s.Set("max", "max-content")
-	s.Set("fit", "fit-content")
-}
-
-func initMaxHeight(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("px", "1px")
-	s.Set("0.5", "0.125rem")
-	s.Set("1", "0.25rem")
-	s.Set("1.5", "0.375rem")
-	s.Set("2", "0.5rem")
-	s.Set("2.5", "0.625rem")
-	s.Set("3", "0.75rem")
-	s.Set("3.5", "0.875rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("96", "24rem")
-	s.Set("none", "none")
-	s.Set("full", "100%")
-	s.Set("screen", "100vh")
-	s.Set("min", "min-content")
-	s.Set("max", "max-content")
-	s.Set("fit", "fit-content")
-}
-
-func initLetterSpacing(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("tighter", "-0.05em")
-	s.Set("tight", "-0.025em")
-	s.Set("normal", "0em")
-	s.Set("wide", "0.025em")
-	s.Set("wider", "0.05em")
-	s.Set("widest", "0.1em")
-}
-
-func initBorderRadius(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("none", "0px")
-	s.Set("sm", "0.125rem")
-	s.Set("", "0.25rem")
-	s.Set("md", "0.375rem")
-	s.Set("lg", "0.5rem")
-	s.Set("xl", "0.75rem")
-	s.Set("2xl", "1rem")
-	s.Set("3xl", "1.5rem")
-	s.Set("full", "9999px")
-}
-
-func initBorderWidth(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("2", "2px")
-	s.Set("4", "4px")
-	s.Set("8", "8px")
-	s.Set("", "1px")
-}
-
-func initBorderStyle(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("solid", "solid")
-	s.Set("dashed", "dashed")
-	s.Set("dotted", "dotted")
-	s.Set("double", "double")
-	s.Set("hidden", "hidden")
-	s.Set("none", "none")
-}
-
-func initFontWeights(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("thin", "100")
-	s.Set("extralight", "200")
-	s.Set("light", "300")
-	s.Set("normal", "400")
-	s.Set("medium", "500")
-	s.Set("semibold", "600")
-	s.Set("bold", "700")
-	s.Set("extrabold", "800")
-	s.Set("black", "900")
-}
-
-func initFontSizes(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set2("xs", "0.75rem", "1rem")
-	s.Set2("sm", "0.875rem", "1.25rem")
-	s.Set2("base", "1rem", "1.5rem")
-	s.Set2("lg", "1.125rem", "1.75rem")
-	s.Set2("xl", "1.25rem", "1.75rem")
-	s.Set2("2xl", "1.5rem", "2rem")
-	s.Set2("3xl", "1.875rem", "2.25rem")
-	s.Set2("4xl", "2.25rem", "2.5rem")
-	s.Set2("5xl", "3rem", "1")
-	s.Set2("6xl", "3.75rem", "1")
-	s.Set2("7xl", "4.5rem", "1")
-	s.Set2("8xl", "6rem", "1")
-	s.Set2("9xl", "8rem", "1")
-}
-
-func initLeading(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("3", ".75rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.50rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("none", "1")
-	s.Set("tight", "1.25")
-	s.Set("snug", "1.375")
-	s.Set("normal", "1.5")
-	s.Set("relaxed", "1.625")
-	s.Set("loose", "2")
-}
+	for _, definition := range catalog.Utilities {
+		c.AddDefinition(definition)
+	}
+
+	c.Functions = append(c.Functions, registeredUtility{"@container/*", namedContainer})
+}
+
+// namedContainer is the @container/sidebar utility.
+func namedContainer(name string) []Declaration {
+	return []Declaration{{"container-type", "inline-size"}, {"container-name", name}}
+}
+
+func initVariants(c *tailwindCollection) {
+	for name, variant := range catalog.Variants {
+		c.Variants[name] = prefixMarker(variant, c.Prefix)
+	}
+
+	for name, value := range c.Theme.Screens {
+		c.Variants[name] = "@media (min-width: " + value + ")"
+	}
+
+	for name, value := range catalog.Scales["containers"] {
+		c.Variants["@"+name] = "@container (min-width: " + value + ")"
+		c.Variants["@max-"+name] = "@container (max-width: " + value + ")"
+	}
+}
+
+// prefixMarker adds the prefix to the .group and .peer marker classes of a
+// variant, so with the prefix "tw-" group-hover selects .tw-group:hover.
+func prefixMarker(variant string, prefix string) string {
+	for _, marker := range []string{".group", ".peer"} {
+		if strings.HasPrefix(variant, marker) {
+			return "." + prefix + variant[1:]
+		}
+	}
+
+	return variant
+}
+
+// getVariant returns a variant by name. Container query variants can also
+// target a named container, like @md/sidebar, or an arbitrary size, like
+// @[30rem]. Attribute variants select data and aria attributes, like
+// data-active, data-[state=open] and aria-[sort=ascending], and arbitrary
+// variants are a selector or an at-rule, like [&>svg] or [@media(hover:hover)].
+func (t *tailwindCollection) getVariant(name string) (string, bool) {
+	variant, ok := t.Variants[name]
+	if ok {
+		return variant, true
+	}
+
+	if strings.HasPrefix(name, "data-") || strings.HasPrefix(name, "aria-") {
+		return attributeVariant(name)
+	}
+
+	if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+		return arbitraryVariant(name)
+	}
+
+	if !strings.HasPrefix(name, "@") {
+		return "", false
+	}
+
+	size, container, named := strings.Cut(name, "/")
+	if named && container == "" {
+		return "", false
+	}
+
+	variant, ok = t.Variants[size]
+	if !ok && len(size) > 3 && strings.HasPrefix(size, "@[") && strings.HasSuffix(size, "]") {
+		variant, ok = "@container (min-width: "+size[2:len(size)-1]+")", true
+	}
+
+	if !ok || !strings.HasPrefix(variant, "@container (") {
+		return "", false
+	}
+
+	if named {
+		variant = "@container " + container + strings.TrimPrefix(variant, "@container")
+	}
+
+	return variant, true
+}
+
+// splitVariants splits "md:hover:bg-red-500" into its variants and the
+// utility. Separators between square brackets don't separate variants.
+func splitVariants(name string, separator string) ([]string, string) {
+	variants := make([]string, 0)
+
+	depth := 0
+	start := 0
+	for i := 0; i < len(name); i++ {
+		switch {
+		case name[i] == '[':
+			depth++
+		case name[i] == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(name[i:], separator):
+			variants = append(variants, name[start:i])
+			start = i + len(separator)
+			i = start - 1
+		}
+	}
+
+	return variants, name[start:]
+}
+
+// attributeVariant returns the selector of data-active, data-[state=open]
+// or aria-[sort=ascending].
+func attributeVariant(name string) (string, bool) {
+	prefix := name[:5]
+	attribute := name[5:]
+
+	if !strings.HasPrefix(attribute, "[") {
+		if prefix == "aria-" || attribute == "" {
+			return "", false
+		}
+
+		return "&[" + prefix + attribute + "]", true
+	}
+
+	if !strings.HasSuffix(attribute, "]") {
+		return "", false
+	}
+
+	key, value, hasValue := strings.Cut(attribute[1:len(attribute)-1], "=")
+	if key == "" {
+		return "", false
+	}
+
+	if !hasValue {
+		return "&[" + prefix + key + "]", true
+	}
+
+	if !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+		value = `"` + value + `"`
+	}
+
+	return "&[" + prefix + key + "=" + value + "]", true
+}
+
+// arbitraryVariant returns the selector or at-rule of [&>svg], [&_p] or
+// [@supports(display:grid)]. Underscores are spaces.
+func arbitraryVariant(name string) (string, bool) {
+	variant := strings.ReplaceAll(name[1:len(name)-1], "_", " ")
+
+	// An at-rule needs a condition after its at-keyword, like [@media_print].
+	if strings.HasPrefix(variant, "@") {
+		rule, condition, ok := strings.Cut(variant, "(")
+		if !ok {
+			if len(strings.Fields(variant)) < 2 {
+				return "", false
+			}
 
-func initJustifyContent(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
+			return variant, true
+		}
 
-	s.Set("normal", "normal")
-	s.Set("start", "flex-start")
-	s.Set("end", "flex-end")
-	s.Set("center", "center")
-	s.Set("between", "space-between")
-	s.Set("around", "space-around")
-	s.Set("evenly", "space-evenly")
-	s.Set("stretch", "stretch")
-}
-
-func initJustifyItems(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("start", "start")
-	s.Set("end", "end")
-	s.Set("center", "center")
-	s.Set("stretch", "stretch")
-}
-
-func initJustifySelf(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("auto", "auto")
-	s.Set("start", "start")
-	s.Set("end", "end")
-	s.Set("center", "center")
-	s.Set("stretch", "stretch")
-}
-
-func initAlignContent(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("normal", "normal")
-	s.Set("center", "center")
-	s.Set("start", "flex-start")
-	s.Set("end", "flex-end")
-	s.Set("between", "space-between")
-	s.Set("around", "space-around")
-	s.Set("evenly", "space-evenly")
-	s.Set("baseline", "baseline")
-	s.Set("stretch", "stretch")
-}
-
-func initAlignItems(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("start", "start")
-	s.Set("end", "end")
-	s.Set("center", "center")
-	s.Set("baseline", "baseline")
-	s.Set("stretch", "stretch")
-}
-
-func initAlignSelf(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("auto", "auto")
-	s.Set("start", "start")
-	s.Set("end", "end")
-	s.Set("center", "center")
-	s.Set("stretch", "stretch")
-	s.Set("baseline", "baseline")
-}
-
-func initShadow(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("sm", "0 1px 2px 0 rgb(0 0 0 / 0.05)")
-	s.Set("", "0 1px 3px 0 rgb(0 0 0 / 0.1), 0 1px 2px -1px rgb(0 0 0 / 0.1)")
-	s.Set("md", "0 4px 6px -1px rgb(0 0 0 / 0.1), 0 2px 4px -2px rgb(0 0 0 / 0.1)")
-	s.Set("lg", "0 10px 15px -3px rgb(0 0 0 / 0.1), 0 4px 6px -4px rgb(0 0 0 / 0.1)")
-	s.Set("xl", "0 20px 25px -5px rgb(0 0 0 / 0.1), 0 8px 10px -6px rgb(0 0 0 / 0.1)")
-	s.Set("2xl", "0 25px 50px -12px rgb(0 0 0 / 0.25)")
-	s.Set("inner", "inset 0 2px 4px 0 rgb(0 0 0 / 0.05)")
-	s.Set("none", "0 0 #0000")
-}
-
-func initOverflow(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("auto", "auto")
-	s.Set("hidden", "hidden")
-	s.Set("clip", "clip")
-	s.Set("visible", "visible")
-	s.Set("scroll", "scroll")
-}
-
-func initObjectFit(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("contain", "contain")
-	s.Set("cover", "cover")
-	s.Set("fill", "fill")
-	s.Set("none", "none")
-	s.Set("scale-down", "scale-down")
-}
-
-func initObjectPosition(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("bottom", "bottom")
-	s.Set("center", "center")
-	s.Set("left", "left")
-	s.Set("left-bottom", "left bottom")
-	s.Set("left-top", "left top")
-	s.Set("right", "right")
-	s.Set("right-bottom", "right-bottom")
-	s.Set("right-top", "right-top")
-	s.Set("top", "top")
-}
-
-func initOpacity(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0")
-	s.Set("5", "0.05")
-	s.Set("10", "0.1")
-	s.Set("20", "0.2")
-	s.Set("25", "0.25")
-	s.Set("30", "0.3")
-	s.Set("40", "0.4")
-	s.Set("50", "0.5")
-	s.Set("60", "0.6")
-	s.Set("70", "0.7")
-	s.Set("75", "0.75")
-	s.Set("80", "0.8")
-	s.Set("90", "0.9")
-	s.Set("95", "0.95")
-	s.Set("100", "1")
-}
-
-func initEase(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("linear", "linear")
-	s.Set("in", "cubic-bezier(0.4, 0, 1, 1)")
-	s.Set("out", "cubic-bezier(0, 0, 0.2, 1)")
-	s.Set("in-out", "cubic-bezier(0.4, 0, 0.2, 1)")
-}
-
-func initDurationDelay(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0s")
-	s.Set("75", "75ms")
-	s.Set("100", "100ms")
-	s.Set("150", "150ms")
-	s.Set("200", "200ms")
-	s.Set("300", "300ms")
-	s.Set("500", "500ms")
-	s.Set("700", "700ms")
-	s.Set("1000", "1000ms")
-}
-
-func initScale(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0")
-	s.Set("50", ".5")
-	s.Set("75", ".75")
-	s.Set("90", ".9")
-	s.Set("95", ".95")
-	s.Set("100", "1")
-	s.Set("105", "1.05")
-	s.Set("110", "1.1")
-	s.Set("125", "1.25")
-	s.Set("150", "1.50")
-}
-
-func initRotate(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0deg")
-	s.Set("1", "1deg")
-	s.Set("2", "2deg")
-	s.Set("3", "3deg")
-	s.Set("6", "6deg")
-	s.Set("12", "12deg")
-	s.Set("45", "45deg")
-	s.Set("90", "90deg")
-	s.Set("180", "180deg")
-}
-
-func initTranslate(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("px", "1px")
-	s.Set("0.5", "0.125rem")
-	s.Set("1", "0.25rem")
-	s.Set("1.5", "0.375rem")
-	s.Set("2", "0.5rem")
-	s.Set("2.5", "0.625rem")
-	s.Set("3", "0.75rem")
-	s.Set("3.5", "0.875rem")
-	s.Set("4", "1rem")
-	s.Set("5", "1.25rem")
-	s.Set("6", "1.5rem")
-	s.Set("7", "1.75rem")
-	s.Set("8", "2rem")
-	s.Set("9", "2.25rem")
-	s.Set("10", "2.5rem")
-	s.Set("11", "2.75rem")
-	s.Set("12", "3rem")
-	s.Set("14", "3.5rem")
-	s.Set("16", "4rem")
-	s.Set("20", "5rem")
-	s.Set("24", "6rem")
-	s.Set("28", "7rem")
-	s.Set("32", "8rem")
-	s.Set("36", "9rem")
-	s.Set("40", "10rem")
-	s.Set("44", "11rem")
-	s.Set("48", "12rem")
-	s.Set("52", "13rem")
-	s.Set("56", "14rem")
-	s.Set("60", "15rem")
-	s.Set("64", "16rem")
-	s.Set("72", "18rem")
-	s.Set("80", "20rem")
-	s.Set("96", "24rem")
-	s.Set("1/2", "50%")
-	s.Set("1/3", "33.333333%")
-	s.Set("2/3", "66.666667%")
-	s.Set("1/4", "25%")
-	s.Set("2/4", "50%")
-	s.Set("3/4", "75%")
-	s.Set("full", "100%")
-}
-
-func initSkew(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0deg")
-	s.Set("1", "1deg")
-	s.Set("2", "2deg")
-	s.Set("3", "3deg")
-	s.Set("6", "6deg")
-	s.Set("12", "12deg")
-}
-
-func initOrigin(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("center", "center")
-	s.Set("top", "top")
-	s.Set("top-right", "top right")
-	s.Set("right", "right")
-	s.Set("bottom-right", "bottom right")
-	s.Set("bottom", "bottom")
-	s.Set("bottom-left", "bottom left")
-	s.Set("left", "left")
-	s.Set("top-left", "top left")
-}
-
-func initZIndex(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0")
-	s.Set("10", "10")
-	s.Set("20", "20")
-	s.Set("30", "30")
-	s.Set("40", "40")
-	s.Set("50", "50")
-	s.Set("auto", "auto")
-}
-
-func initOutlineWidth(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("0", "0px")
-	s.Set("1", "1px")
-	s.Set("2", "2px")
-	s.Set("4", "4px")
-	s.Set("8", "8px")
-}
-
-func initOutlineStyle(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("none", "!outline: 2px solid transparent; outline-offset: 2px;")
-	s.Set("", "solid")
-	s.Set("dashed", "dashed")
-	s.Set("dotted", "dotted")
-	s.Set("double", "double")
-}
-
-func initCursors(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("auto", "auto")
-	s.Set("default", "default")
-	s.Set("pointer", "pointer")
-	s.Set("wait", "wait")
-	s.Set("text", "text")
-	s.Set("move", "move")
-	s.Set("help", "help")
-	s.Set("not-allowed", "not-allowed")
-}
-
-func initPointerEvents(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
-
-	s.Set("none", "none")
-	s.Set("auto", "auto")
-}
+		rule = strings.TrimSpace(rule)
+		if rule == "@" || strings.TrimSpace(strings.TrimSuffix(condition, ")")) == "" {
+			return "", false
+		}
 
-func initTextAlign(c *tailwindCollection, prefix string, template string) {
-	s := createHelper(c, prefix, template)
+		return rule + " (" + condition, true
+	}
+
+	if !strings.Contains(variant, "&") {
+		return "", false
+	}
+
+	return variant, true
+}
+
+// arbitraryProperty returns the utility of [mask-type:luminance].
+// Underscores in the value are spaces.
+func arbitraryProperty(name string) *utility {
+	if !strings.HasPrefix(name, ".[") || !strings.HasSuffix(name, "]") {
+		return nil
+	}
+
+	property, value, ok := strings.Cut(name[2:len(name)-1], ":")
+	if !ok || property == "" || value == "" || !reProperty.MatchString(property) {
+		return nil
+	}
 
-	s.Set("left", "left")
-	s.Set("center", "center")
-	s.Set("right", "right")
-	s.Set("justify", "justify")
-	s.Set("start", "start")
-	s.Set("end", "end")
+	return &utility{Declarations: property + ": " + strings.ReplaceAll(value, "_", " ") + ";"}
 }
 
-type helper struct {
-	Collection   *tailwindCollection
-	NamePrefix   string
-	TextTemplate string
+// wrapVariant moves the contents of a utility into the selector or at-rule
+// of a variant.
+func wrapVariant(n *selectorNode, variant string) *selectorNode {
+	var wrapper node
+	if strings.HasPrefix(variant, "@") {
+		wrapper = newAtRuleNode(variant, 0)
+	} else {
+		wrapper = newSelectorNode(appendSelectors(nil, variant), 0)
+	}
+
+	wrapper.SetChildren(n.Children)
+
+	result := selectorNode{}
+	result.Children = []node{wrapper}
+
+	return &result
 }
 
-func createHelper(c *tailwindCollection, namePrefix string, textTemplate string) *helper {
-	return &helper{Collection: c, NamePrefix: namePrefix, TextTemplate: textTemplate}
+func (t *tailwindCollection) AddDefinition(definition utilityDefinition) {
+	base := utility{
+		Template:   definition.Template,
+		Selector:   definition.Selector,
+		Keyframes:  definition.Keyframes,
+		Responsive: definition.Responsive,
+	}
+
+	if len(definition.Values) == 0 {
+		t.Items["."+definition.Pattern] = &base
+		return
+	}
+
+	base.Modifiers = definition.Modifiers
+	negative := slices.Contains(definition.Modifiers, "negative")
+
+	for _, scaleName := range definition.Values {
+		for key, values := range t.Scale(scaleName) {
+			name := "." + expandPattern(definition.Pattern, key)
+
+			u := base
+			u.Values = values
+			t.Items[name] = &u
+
+			if negative {
+				value, ok := negateValue(values[0])
+				if ok {
+					negated := base
+					negated.Values = slices.Clone(values)
+					negated.Values[0] = value
+					t.Items[".-"+name[1:]] = &negated
+				}
+			}
+		}
+	}
 }
 
-func (h *helper) Set(name string, value string) {
-	n := "." + h.NamePrefix
-	if name != "" {
-		n += "-" + name
+// Scale returns a value scale from the theme or, when the theme doesn't
+// have a scale with that name, from the catalog.
+func (t *tailwindCollection) Scale(name string) map[string][]string {
+	scale := t.Theme.Scale(name)
+	if scale != nil {
+		return scale
+	}
+
+	scale = make(map[string][]string)
+
+	values, ok := catalog.Scales[name]
+	if !ok {
+		panic(fmt.Sprintf("tailwind.json: unknown scale '%s'", name))
 	}
-	h.Collection.Add(n, strings.Replace(h.TextTemplate, "$1", value, -1))
+
+	for key, value := range values {
+		if key == "DEFAULT" {
+			key = ""
+		}
+
+		scale[key] = []string{value}
+	}
+
+	return scale
 }
 
-func (h *helper) Set2(name string, value1 string, value2 string) {
-	str := strings.Replace(h.TextTemplate, "$1", value1, -1)
-	str = strings.Replace(str, "$2", value2, -1)
-	name = "." + h.NamePrefix + "-" + name
-	h.Collection.Add(name, str)
+func expandPattern(pattern string, key string) string {
+	if key == "" {
+		return strings.TrimSuffix(strings.TrimSuffix(pattern, "*"), "-")
+	}
+
+	return strings.Replace(pattern, "*", key, 1)
+}
+
+func negateValue(value string) (string, bool) {
+	if strings.HasPrefix(value, "-") {
+		return value[1:], true
+	}
+
+	if strings.HasPrefix(value, "var(") {
+		return "calc(" + value + " * -1)", true
+	}
+
+	if value == "" || !strings.ContainsAny(value[:1], "0123456789.") {
+		return "", false
+	}
+
+	return "-" + value, true
+}
+
+// colorWithOpacity applies an opacity to a color, using the rgb() notation
+// for hex colors, the alpha of rgb() and oklch() colors, and color-mix() for
+// everything else.
+func colorWithOpacity(color string, opacity string) string {
+	r, g, b, ok := parseHexColor(color)
+	if ok {
+		return fmt.Sprintf("rgb(%d %d %d / %s)", r, g, b, opacity)
+	}
+
+	if (strings.HasPrefix(color, "rgb(") || strings.HasPrefix(color, "oklch(")) && !strings.ContainsAny(color, ",/") {
+		return strings.TrimSuffix(color, ")") + " / " + opacity + ")"
+	}
+
+	percentage, err := strconv.ParseFloat(opacity, 64)
+	if err != nil {
+		return color
+	}
+
+	return fmt.Sprintf("color-mix(in srgb, %s %s%%, transparent)", color, strconv.FormatFloat(percentage*100, 'f', -1, 64))
+}
+
+func parseHexColor(color string) (int, int, int, bool) {
+	if !strings.HasPrefix(color, "#") {
+		return 0, 0, 0, false
+	}
+
+	hex := color[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+
+	return int(value >> 16), int(value >> 8 & 0xff), int(value & 0xff), true
 }
@@ -0,0 +1,190 @@
+package tailless
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// renderUtility renders a utility used as a mixin in the rule ".x".
+func renderUtility(t *testing.T, tailwind *tailwindCollection, name string) string {
+	t.Helper()
+
+	utility := tailwind.Get(name)
+	if utility == nil {
+		t.Fatalf("utility '%s' not found", name)
+	}
+
+	rule := newSelectorNode([]string{".x"}, 0)
+	rule.Children = utility.Children
+
+	tree := rootNode{}
+	tree.AddChild(rule)
+
+	expandSelectors(&tree)
+	tree.HideIfEmpty()
+
+	var b strings.Builder
+	tree.Render(&b)
+
+	return b.String()
+}
+
+func TestCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{".m-4", ".x {\n  margin: 1rem;\n}\n"},
+		{".grid", ".x {\n  display: grid;\n}\n"},
+		{".line-through", ".x {\n  text-decoration: line-through;\n}\n"},
+		{".object-right-bottom", ".x {\n  object-position: right bottom;\n}\n"},
+		{".-mt-2", ".x {\n  margin-top: -0.5rem;\n}\n"},
+		{".-m-px", ".x {\n  margin: -1px;\n}\n"},
+		{".bg-red-500/50", ".x {\n  background-color: rgb(239 68 68 / 0.5);\n}\n"},
+		{".text-current/50", ".x {\n  color: color-mix(in srgb, currentColor 50%, transparent);\n}\n"},
+		{".text-sm", ".x {\n  font-size: 0.875rem;\n  line-height: 1.25rem;\n}\n"},
+		{".rounded", ".x {\n  border-radius: 0.25rem;\n}\n"},
+		{".hover:underline", ".x:hover {\n  text-decoration: underline;\n}\n"},
+		{".!p-4", ".x {\n  padding: 1rem !important;\n}\n"},
+		{`.[content:"$"]`, ".x {\n  content: \"$\";\n}\n"},
+		{".[mask-type:luminance]", ".x {\n  mask-type: luminance;\n}\n"},
+	}
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := renderUtility(t, tailwind, test.name)
+			if actual != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCatalogMissing(t *testing.T) {
+	names := []string{".-bg-red-500", ".bg-red-500/13", ".p-4/50", ".m-nope", ".nope:p-4"}
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	for _, name := range names {
+		if tailwind.Get(name) != nil {
+			t.Errorf("expected no utility for '%s'", name)
+		}
+	}
+}
+
+func TestProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{".p-4", []string{}},
+		{".ring-2", []string{"--tw-ring-offset-shadow", "--tw-ring-inset", "--tw-ring-offset-width", "--tw-ring-offset-color", "--tw-ring-shadow", "--tw-ring-color", "--tw-shadow"}},
+		{".shadow", []string{"--tw-shadow", "--tw-ring-offset-shadow", "--tw-ring-shadow"}},
+		{".via-blue-500", []string{"--tw-gradient-via-stop", "--tw-gradient-via-position"}},
+		{".blur", []string{"--tw-blur", "--tw-brightness", "--tw-contrast", "--tw-grayscale", "--tw-hue-rotate", "--tw-invert", "--tw-saturate", "--tw-sepia", "--tw-drop-shadow"}},
+		{".ordinal", []string{"--tw-ordinal", "--tw-slashed-zero", "--tw-numeric-figure", "--tw-numeric-spacing", "--tw-numeric-fraction"}},
+		{".backdrop-blur", []string{"--tw-backdrop-blur", "--tw-backdrop-brightness", "--tw-backdrop-contrast", "--tw-backdrop-grayscale", "--tw-backdrop-hue-rotate", "--tw-backdrop-invert", "--tw-backdrop-opacity", "--tw-backdrop-saturate", "--tw-backdrop-sepia"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+			tailwind.Get(test.name)
+
+			names := make([]string, 0)
+			for _, property := range tailwind.GetProperties() {
+				names = append(names, strings.TrimPrefix(property.(*atRuleNode).Text, "@property "))
+			}
+
+			if !slices.Equal(names, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, names)
+			}
+		})
+	}
+}
+
+func TestPropertyRule(t *testing.T) {
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+	tailwind.Get(".shadow")
+
+	tree := rootNode{}
+	tree.AddChild(tailwind.GetProperties()[0])
+
+	var b strings.Builder
+	tree.Render(&b)
+
+	expected := "@property --tw-shadow {\n  syntax: \"*\";\n  inherits: false;\n}\n"
+	if b.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b.String())
+	}
+}
+
+func TestPrefixedGroupVariant(t *testing.T) {
+	tailwind := newTailwindCollection(newDefaultTheme(), "tw-", ":")
+
+	actual := renderUtility(t, tailwind, ".group-hover:tw-underline")
+	expected := ".tw-group:hover .x {\n  text-decoration: underline;\n}\n"
+	if actual != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestContainerVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{".@md:p-4", "@container (min-width: 28rem) {\n.x {\n  padding: 1rem;\n}\n}\n"},
+		{".@max-md:p-4", "@container (max-width: 28rem) {\n.x {\n  padding: 1rem;\n}\n}\n"},
+		{".@md/sidebar:p-4", "@container sidebar (min-width: 28rem) {\n.x {\n  padding: 1rem;\n}\n}\n"},
+		{".@[30rem]:p-4", "@container (min-width: 30rem) {\n.x {\n  padding: 1rem;\n}\n}\n"},
+	}
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := renderUtility(t, tailwind, test.name)
+			if actual != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, actual)
+			}
+		})
+	}
+
+	for _, name := range []string{".@[]:p-4", ".@md/:p-4", ".@nope:p-4"} {
+		if tailwind.Get(name) != nil {
+			t.Errorf("expected no utility for '%s'", name)
+		}
+	}
+}
+
+func TestArbitraryVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{".[&>svg]:p-4", ".x>svg {\n  padding: 1rem;\n}\n"},
+		{".[@media_print]:p-4", "@media print {\n.x {\n  padding: 1rem;\n}\n}\n"},
+		{".[@supports(display:grid)]:p-4", "@supports (display:grid) {\n.x {\n  padding: 1rem;\n}\n}\n"},
+	}
+
+	tailwind := newTailwindCollection(newDefaultTheme(), "", ":")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := renderUtility(t, tailwind, test.name)
+			if actual != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, actual)
+			}
+		})
+	}
+
+	for _, name := range []string{".[@media]:p-4", ".[@supports()]:p-4", ".[@(hover:hover)]:p-4", ".[p]:p-4"} {
+		if tailwind.Get(name) != nil {
+			t.Errorf("expected no utility for '%s'", name)
+		}
+	}
+}
@@ -0,0 +1,680 @@
+package tailless
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var reThemeFunction = regexp.MustCompile(`\btheme\(((?:[^()]|\([^()]*\))*)\)`)
+var reScreenFunction = regexp.MustCompile(`\bscreen\(([^()]*)\)`)
+
+type theme struct {
+	Colors       stringMap
+	Spacing      stringMap
+	FontSize     map[string][]string
+	BorderRadius stringMap
+	BoxShadow    stringMap
+	Screens      stringMap
+	FontFamily   map[string][]string
+	BorderColor  stringMap
+
+	// CustomProperties makes the scales refer to the custom properties of
+	// the theme, like var(--color-emerald-700), instead of the values.
+	CustomProperties bool
+}
+
+// The custom property prefixes of the theme scales, in the order they are
+// emitted in the :root rule.
+var themeProperties = []struct {
+	Scale  string
+	Prefix string
+}{
+	{"colors", "color"},
+	{"spacing", "spacing"},
+	{"fontFamily", "font"},
+	{"fontSize", "text"},
+	{"borderRadius", "radius"},
+	{"boxShadow", "shadow"},
+	{"screens", "breakpoint"},
+}
+
+type themeFile struct {
+	Extend   themeSection `json:"extend"`
+	Override themeSection `json:"override"`
+}
+
+type themeSection struct {
+	Colors       map[string]any `json:"colors"`
+	Spacing      stringMap      `json:"spacing"`
+	FontSize     map[string]any `json:"fontSize"`
+	BorderRadius stringMap      `json:"borderRadius"`
+	BoxShadow    stringMap      `json:"boxShadow"`
+	Screens      stringMap      `json:"screens"`
+	FontFamily   map[string]any `json:"fontFamily"`
+	BorderColor  stringMap      `json:"borderColor"`
+}
+
+func newDefaultTheme() *theme {
+	t := theme{}
+
+	t.setColors(*colors)
+
+	t.Spacing = stringMap{
+		"0":   "0px",
+		"px":  "1px",
+		"0.5": "0.125rem",
+		"1":   "0.25rem",
+		"1.5": "0.375rem",
+		"2":   "0.5rem",
+		"2.5": "0.625rem",
+		"3":   "0.75rem",
+		"3.5": "0.875rem",
+		"4":   "1rem",
+		"5":   "1.25rem",
+		"6":   "1.5rem",
+		"7":   "1.75rem",
+		"8":   "2rem",
+		"9":   "2.25rem",
+		"10":  "2.5rem",
+		"11":  "2.75rem",
+		"12":  "3rem",
+		"14":  "3.5rem",
+		"16":  "4rem",
+		"20":  "5rem",
+		"24":  "6rem",
+		"28":  "7rem",
+		"32":  "8rem",
+		"36":  "9rem",
+		"40":  "10rem",
+		"44":  "11rem",
+		"48":  "12rem",
+		"52":  "13rem",
+		"56":  "14rem",
+		"60":  "15rem",
+		"64":  "16rem",
+		"72":  "18rem",
+		"80":  "20rem",
+		"88":  "22rem",
+		"96":  "24rem",
+	}
+
+	t.FontSize = map[string][]string{
+		"xs":   {"0.75rem", "1rem"},
+		"sm":   {"0.875rem", "1.25rem"},
+		"base": {"1rem", "1.5rem"},
+		"lg":   {"1.125rem", "1.75rem"},
+		"xl":   {"1.25rem", "1.75rem"},
+		"2xl":  {"1.5rem", "2rem"},
+		"3xl":  {"1.875rem", "2.25rem"},
+		"4xl":  {"2.25rem", "2.5rem"},
+		"5xl":  {"3rem", "1"},
+		"6xl":  {"3.75rem", "1"},
+		"7xl":  {"4.5rem", "1"},
+		"8xl":  {"6rem", "1"},
+		"9xl":  {"8rem", "1"},
+	}
+
+	t.BorderRadius = stringMap{
+		"none": "0px",
+		"sm":   "0.125rem",
+		"":     "0.25rem",
+		"md":   "0.375rem",
+		"lg":   "0.5rem",
+		"xl":   "0.75rem",
+		"2xl":  "1rem",
+		"3xl":  "1.5rem",
+		"full": "9999px",
+	}
+
+	t.BoxShadow = stringMap{
+		"sm":    "0 1px 2px 0 rgb(0 0 0 / 0.05)",
+		"":      "0 1px 3px 0 rgb(0 0 0 / 0.1), 0 1px 2px -1px rgb(0 0 0 / 0.1)",
+		"md":    "0 4px 6px -1px rgb(0 0 0 / 0.1), 0 2px 4px -2px rgb(0 0 0 / 0.1)",
+		"lg":    "0 10px 15px -3px rgb(0 0 0 / 0.1), 0 4px 6px -4px rgb(0 0 0 / 0.1)",
+		"xl":    "0 20px 25px -5px rgb(0 0 0 / 0.1), 0 8px 10px -6px rgb(0 0 0 / 0.1)",
+		"2xl":   "0 25px 50px -12px rgb(0 0 0 / 0.25)",
+		"inner": "inset 0 2px 4px 0 rgb(0 0 0 / 0.05)",
+		"none":  "0 0 #0000",
+	}
+
+	t.Screens = stringMap{
+		"sm":  "640px",
+		"md":  "768px",
+		"lg":  "1024px",
+		"xl":  "1280px",
+		"2xl": "1536px",
+	}
+
+	t.FontFamily = map[string][]string{
+		"sans":  {"ui-sans-serif", "system-ui", "sans-serif", `"Apple Color Emoji"`, `"Segoe UI Emoji"`, `"Segoe UI Symbol"`, `"Noto Color Emoji"`},
+		"serif": {"ui-serif", "Georgia", "Cambria", `"Times New Roman"`, "Times", "serif"},
+		"mono":  {"ui-monospace", "SFMono-Regular", "Menlo", "Monaco", "Consolas", `"Liberation Mono"`, `"Courier New"`, "monospace"},
+	}
+
+	return &t
+}
+
+func (t *theme) setColors(palette stringMap) {
+	t.Colors = maps.Clone(palette)
+	t.Colors["inherit"] = "inherit"
+	t.Colors["current"] = "currentColor"
+	t.Colors["transparent"] = "transparent"
+	t.Colors["black"] = "#000000"
+	t.Colors["white"] = "#ffffff"
+
+	t.BorderColor = stringMap{
+		"": t.Colors["gray-200"],
+	}
+}
+
+// SetPalette replaces the colors by the Tailwind "v3" (hex) or "v4" (OKLCH)
+// palette.
+func (t *theme) SetPalette(name string) error {
+	switch name {
+	case "", "v3":
+		t.setColors(*colors)
+	case "v4":
+		t.setColors(*colorsV4)
+	default:
+		return fmt.Errorf("Unknown palette '%s'", name)
+	}
+
+	return nil
+}
+
+// FormatColors converts the colors of the theme to the "hex", "rgb" or
+// "oklch" format.
+func (t *theme) FormatColors(format string) error {
+	err := validColorFormat(format)
+	if err != nil || format == "" {
+		return err
+	}
+
+	for name, value := range t.Colors {
+		t.Colors[name] = formatColor(value, format)
+	}
+
+	for name, value := range t.BorderColor {
+		t.BorderColor[name] = formatColor(value, format)
+	}
+
+	return nil
+}
+
+// Load reads a JSON theme file. Scales in its "override" section replace
+// the current scale completely, scales in its "extend" section are merged
+// into it.
+func (t *theme) Load(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	file := themeFile{}
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return fmt.Errorf("Theme '%s': %v", filename, err)
+	}
+
+	err = t.apply(file.Override, true)
+	if err != nil {
+		return fmt.Errorf("Theme '%s': %v", filename, err)
+	}
+
+	err = t.apply(file.Extend, false)
+	if err != nil {
+		return fmt.Errorf("Theme '%s': %v", filename, err)
+	}
+
+	return nil
+}
+
+func (t *theme) apply(section themeSection, override bool) error {
+	if section.Colors != nil {
+		colors, err := readColors(section.Colors)
+		if err != nil {
+			return err
+		}
+
+		t.Colors = applyScale(t.Colors, colors, override)
+	}
+
+	if section.Spacing != nil {
+		t.Spacing = applyScale(t.Spacing, section.Spacing, override)
+	}
+
+	if section.FontSize != nil {
+		fontSize, err := readFontSizes(section.FontSize)
+		if err != nil {
+			return err
+		}
+
+		t.FontSize = applyScale(t.FontSize, fontSize, override)
+	}
+
+	if section.BorderRadius != nil {
+		t.BorderRadius = applyScale(t.BorderRadius, readDefaults(section.BorderRadius), override)
+	}
+
+	if section.BoxShadow != nil {
+		t.BoxShadow = applyScale(t.BoxShadow, readDefaults(section.BoxShadow), override)
+	}
+
+	if section.Screens != nil {
+		t.Screens = applyScale(t.Screens, section.Screens, override)
+	}
+
+	if section.FontFamily != nil {
+		fontFamily, err := readFontFamilies(section.FontFamily)
+		if err != nil {
+			return err
+		}
+
+		t.FontFamily = applyScale(t.FontFamily, fontFamily, override)
+	}
+
+	if section.BorderColor != nil {
+		t.BorderColor = applyScale(t.BorderColor, readDefaults(section.BorderColor), override)
+	}
+
+	return nil
+}
+
+// Value looks up a theme value by its path, like "colors.emerald.700",
+// "spacing.4", "spacing[2.5]" or "screens.md".
+func (t *theme) Value(path string) (string, bool) {
+	path = strings.ReplaceAll(strings.ReplaceAll(path, "[", "."), "]", "")
+
+	section, key, _ := strings.Cut(strings.TrimSpace(path), ".")
+
+	keys := []string{key, strings.ReplaceAll(key, ".", "-")}
+	for i, k := range keys {
+		if k == "DEFAULT" {
+			keys[i] = ""
+		} else {
+			keys[i] = strings.TrimSuffix(k, "-DEFAULT")
+		}
+	}
+
+	for _, k := range keys {
+		var value string
+		var ok bool
+
+		switch section {
+		case "colors":
+			value, ok = t.Colors[k]
+		case "spacing":
+			value, ok = t.Spacing[k]
+		case "borderRadius":
+			value, ok = t.BorderRadius[k]
+		case "boxShadow":
+			value, ok = t.BoxShadow[k]
+		case "screens":
+			value, ok = t.Screens[k]
+		case "borderColor":
+			value, ok = t.BorderColor[k]
+		case "fontSize":
+			var values []string
+			values, ok = t.FontSize[k]
+			if ok {
+				value = values[0]
+			}
+		case "fontFamily":
+			var families []string
+			families, ok = t.FontFamily[k]
+			value = fontFamilyValue(families)
+		}
+
+		if ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// Scale returns a theme scale as utility values, or nil when the theme has
+// no scale with that name.
+func (t *theme) Scale(name string) map[string][]string {
+	scale := t.values(name)
+	if scale == nil || !t.CustomProperties {
+		return scale
+	}
+
+	prefix := themePropertyPrefix(name)
+	references := make(map[string][]string)
+
+	for key, values := range scale {
+		property := customPropertyName(prefix, key)
+
+		references[key] = []string{"var(" + property + ")"}
+		if len(values) > 1 {
+			references[key] = append(references[key], "var("+property+"--line-height)")
+		}
+	}
+
+	return references
+}
+
+// CustomPropertyDeclarations returns the declarations of the :root rule
+// that defines the theme as custom properties.
+func (t *theme) CustomPropertyDeclarations() []string {
+	declarations := make([]string, 0)
+
+	for _, p := range themeProperties {
+		scale := t.values(p.Scale)
+
+		keys := slices.Collect(maps.Keys(scale))
+		slices.SortFunc(keys, compareScaleKeys)
+
+		for _, key := range keys {
+			property := customPropertyName(p.Prefix, key)
+			values := scale[key]
+
+			declarations = append(declarations, property+": "+values[0]+";")
+			if len(values) > 1 {
+				declarations = append(declarations, property+"--line-height: "+values[1]+";")
+			}
+		}
+	}
+
+	return declarations
+}
+
+// compareScaleKeys orders scale keys by name and then by number, so
+// "red-50" comes before "red-100" and "0.5" before "1".
+func compareScaleKeys(a, b string) int {
+	aPos := strings.LastIndex(a, "-")
+	bPos := strings.LastIndex(b, "-")
+
+	if a[:max(aPos, 0)] != b[:max(bPos, 0)] {
+		return strings.Compare(a, b)
+	}
+
+	aNumber, aErr := strconv.ParseFloat(a[aPos+1:], 64)
+	bNumber, bErr := strconv.ParseFloat(b[bPos+1:], 64)
+
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(aNumber, bNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func themePropertyPrefix(scale string) string {
+	for _, p := range themeProperties {
+		if p.Scale == scale {
+			return p.Prefix
+		}
+	}
+
+	return scale
+}
+
+// customPropertyName returns the name of the custom property of a scale key,
+// like --spacing-0\.5. The DEFAULT key is the prefix itself.
+func customPropertyName(prefix string, key string) string {
+	if key == "" {
+		return "--" + prefix
+	}
+
+	key = strings.NewReplacer(".", `\.`, "/", `\/`).Replace(key)
+
+	return "--" + prefix + "-" + key
+}
+
+func (t *theme) values(name string) map[string][]string {
+	switch name {
+	case "colors":
+		return singleValues(t.Colors)
+	case "spacing":
+		return singleValues(t.Spacing)
+	case "fontSize":
+		return t.FontSize
+	case "borderRadius":
+		return singleValues(t.BorderRadius)
+	case "boxShadow":
+		return singleValues(t.BoxShadow)
+	case "screens":
+		return singleValues(t.Screens)
+	case "fontFamily":
+		scale := make(map[string][]string)
+		for name, families := range t.FontFamily {
+			scale[name] = []string{fontFamilyValue(families)}
+		}
+		return scale
+	}
+
+	return nil
+}
+
+// ReplaceFunctions replaces theme(path) and theme(path, fallback) by the
+// theme value.
+func (t *theme) ReplaceFunctions(text string) (string, error) {
+	var err error
+
+	text = reThemeFunction.ReplaceAllStringFunc(text, func(match string) string {
+		arguments := reThemeFunction.FindStringSubmatch(match)[1]
+		path, fallback, hasFallback := strings.Cut(arguments, ",")
+		path = strings.Trim(strings.TrimSpace(path), `"'`)
+
+		value, ok := t.Value(path)
+		if ok {
+			return value
+		}
+
+		if hasFallback {
+			return strings.TrimSpace(fallback)
+		}
+
+		err = fmt.Errorf("Theme value '%s' not found", path)
+		return match
+	})
+
+	return text, err
+}
+
+// ReplaceScreens replaces screen(md) by the min-width media query of the
+// screen, as in "@media screen(md)".
+func (t *theme) ReplaceScreens(text string) (string, error) {
+	var err error
+
+	text = reScreenFunction.ReplaceAllStringFunc(text, func(match string) string {
+		name := strings.Trim(strings.TrimSpace(reScreenFunction.FindStringSubmatch(match)[1]), `"'`)
+
+		value, ok := t.Screens[name]
+		if !ok {
+			err = fmt.Errorf("Screen '%s' not found", name)
+			return match
+		}
+
+		return "(min-width: " + value + ")"
+	})
+
+	return text, err
+}
+
+// SortedScreens returns the names of the screens ordered by their width.
+func (t *theme) SortedScreens() []string {
+	names := slices.Collect(maps.Keys(t.Screens))
+
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Compare(cssLength(t.Screens[a]), cssLength(t.Screens[b]))
+	})
+
+	return names
+}
+
+// cssLength converts a px, rem or em length to pixels.
+func cssLength(value string) float64 {
+	unit := strings.TrimLeft(value, "0123456789.")
+	number, err := strconv.ParseFloat(strings.TrimSuffix(value, unit), 64)
+	if err != nil {
+		return 0
+	}
+
+	if unit == "rem" || unit == "em" {
+		return number * 16
+	}
+
+	return number
+}
+
+func singleValues(values stringMap) map[string][]string {
+	scale := make(map[string][]string)
+
+	for name, value := range values {
+		scale[name] = []string{value}
+	}
+
+	return scale
+}
+
+func applyScale[V any](current map[string]V, values map[string]V, override bool) map[string]V {
+	if override {
+		return values
+	}
+
+	maps.Copy(current, values)
+
+	return current
+}
+
+// readColors flattens nested color objects, so { "brand": { "500": "#..." } }
+// becomes "brand-500". A "DEFAULT" key maps to the name of its parent, and
+// "palette(#0f766e)" generates brand and the shades brand-50 to brand-950.
+func readColors(values map[string]any) (stringMap, error) {
+	colors := make(stringMap)
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			base, ok := paletteArgument(v)
+			if !ok {
+				colors[name] = v
+				continue
+			}
+
+			palette, err := generatePalette(base)
+			if err != nil {
+				return nil, err
+			}
+
+			colors[name] = base
+			for shade, color := range palette {
+				colors[name+"-"+shade] = color
+			}
+		case map[string]any:
+			for shade, shadeValue := range v {
+				s, ok := shadeValue.(string)
+				if !ok {
+					return nil, fmt.Errorf("Invalid color '%s-%s'", name, shade)
+				}
+
+				if shade == "DEFAULT" {
+					colors[name] = s
+				} else {
+					colors[name+"-"+shade] = s
+				}
+			}
+		default:
+			return nil, fmt.Errorf("Invalid color '%s'", name)
+		}
+	}
+
+	return colors, nil
+}
+
+// readFontSizes accepts "size", ["size", "line-height"] and
+// ["size", { "lineHeight": "line-height" }].
+func readFontSizes(values map[string]any) (map[string][]string, error) {
+	fontSizes := make(map[string][]string)
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			fontSizes[name] = []string{v}
+		case []any:
+			sizes := make([]string, 0)
+			for _, item := range v {
+				switch i := item.(type) {
+				case string:
+					sizes = append(sizes, i)
+				case map[string]any:
+					lineHeight, ok := i["lineHeight"].(string)
+					if ok {
+						sizes = append(sizes, lineHeight)
+					}
+				}
+			}
+
+			if len(sizes) == 0 {
+				return nil, fmt.Errorf("Invalid font size '%s'", name)
+			}
+
+			fontSizes[name] = sizes
+		default:
+			return nil, fmt.Errorf("Invalid font size '%s'", name)
+		}
+	}
+
+	return fontSizes, nil
+}
+
+// readFontFamilies accepts both "a, b" and ["a", "b"].
+func readFontFamilies(values map[string]any) (map[string][]string, error) {
+	fontFamilies := make(map[string][]string)
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			fontFamilies[name] = []string{v}
+		case []any:
+			families := make([]string, 0)
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("Invalid font family '%s'", name)
+				}
+
+				families = append(families, s)
+			}
+
+			fontFamilies[name] = families
+		default:
+			return nil, fmt.Errorf("Invalid font family '%s'", name)
+		}
+	}
+
+	return fontFamilies, nil
+}
+
+func readDefaults(values stringMap) stringMap {
+	result := make(stringMap)
+
+	for name, value := range values {
+		if name == "DEFAULT" {
+			name = ""
+		}
+
+		result[name] = value
+	}
+
+	return result
+}
+
+func fontFamilyValue(families []string) string {
+	return strings.Join(families, ", ")
+}
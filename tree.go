@@ -243,6 +243,8 @@ func (n *selectorNode) Render(w io.Writer) {
 		}
 	}
 
+	declarationNodes = removeDuplicateDeclarations(declarationNodes)
+
 	if len(declarationNodes) > 0 {
 
 		last := len(n.MergedSelectors) - 1
@@ -292,6 +294,11 @@ func (n *declarationNode) ReplaceVariables(variables *variablesCollection) error
 	for {
 		match := reVariable.FindStringIndex(text)
 		if match == nil {
+			text, err := variables.ReplaceFunctions(text, false)
+			if err != nil {
+				return fmt.Errorf("Line %d: %v", n.LineNumber, err)
+			}
+
 			n.Text = text
 			return nil
 		}
@@ -355,12 +362,27 @@ func (n *atRuleNode) ExpandSelectors(parentSelectors []string) {
 	}
 }
 
+func (n *atRuleNode) GetCopy() node {
+	copy := newAtRuleNode(n.Text, n.LineNumber)
+
+	for _, child := range n.Children {
+		copy.Children = append(copy.Children, child.GetCopy())
+	}
+
+	return copy
+}
+
 func (n *atRuleNode) ReplaceVariables(variables *variablesCollection) error {
 	text := n.Text[1:]
 
 	for {
 		match := reVariable.FindStringIndex(text)
 		if match == nil {
+			text, err := variables.ReplaceFunctions(text, true)
+			if err != nil {
+				return fmt.Errorf("Line %d: %v", n.LineNumber, err)
+			}
+
 			n.Text = "@" + text
 			return nil
 		}
@@ -401,7 +423,13 @@ func (n *atRuleNode) Render(w io.Writer) {
 		}
 	}
 
-	if len(declarationNodes) > 0 {
+	declarationNodes = removeDuplicateDeclarations(declarationNodes)
+
+	if len(declarationNodes) > 0 && isTopLevel(n.ParentSelectors) {
+		for _, child := range declarationNodes {
+			child.Render(w)
+		}
+	} else if len(declarationNodes) > 0 {
 		last := len(n.ParentSelectors) - 1
 
 		for i, selector := range n.ParentSelectors {
@@ -435,6 +463,33 @@ func (n *atRuleNode) Dump(indent string) {
 	}
 }
 
+// isTopLevel reports whether an at-rule is outside any rule, like
+// @font-face or @property, so its declarations aren't wrapped in a rule.
+func isTopLevel(parentSelectors []string) bool {
+	return len(parentSelectors) == 0 || (len(parentSelectors) == 1 && parentSelectors[0] == "")
+}
+
+// removeDuplicateDeclarations drops declarations that are repeated later on
+// in the same rule. Utilities that compose through custom properties, like
+// .blur-sm and .grayscale, all set the same filter declaration.
+func removeDuplicateDeclarations(nodes []node) []node {
+	texts := make([]string, len(nodes))
+	for i, n := range nodes {
+		var b strings.Builder
+		n.Render(&b)
+		texts[i] = b.String()
+	}
+
+	result := make([]node, 0)
+	for i, n := range nodes {
+		if !slices.Contains(texts[i+1:], texts[i]) {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
 type importNode struct {
 	baseNode
 	Text string
@@ -448,6 +503,124 @@ func (n *importNode) Dump(indent string) {
 	fmt.Printf("%sImportNode: %s\n", indent, n.Text)
 }
 
+type tailwindNode struct {
+	baseNode
+	Directive string
+	Text      string
+}
+
+func (n *tailwindNode) GetType() string {
+	return "tailwind-" + n.Directive
+}
+
+func (n *tailwindNode) ExpandSelectors(parentSelectors []string) {
+	for _, child := range n.Children {
+		child.ExpandSelectors(parentSelectors)
+	}
+}
+
+func (n *tailwindNode) HideIfEmpty() bool {
+	isEmpty := n.Text == ""
+	for _, child := range n.Children {
+		if !child.HideIfEmpty() {
+			isEmpty = false
+		}
+	}
+
+	n.Hidden = isEmpty
+	return isEmpty
+}
+
+func (n *tailwindNode) Render(w io.Writer) {
+	fmt.Fprint(w, n.Text)
+
+	for _, child := range n.Children {
+		child.Render(w)
+	}
+}
+
+func (n *tailwindNode) Dump(indent string) {
+	fmt.Printf("%sTailwindNode: %s\n", indent, n.Directive)
+	for _, child := range n.Children {
+		child.Dump(indent + "  ")
+	}
+}
+
+// A variantNode is the variant of a function registered with
+// RegisterVariant. The function is called for every selector of the rule the
+// variant ends up in, so its selector or at-rule is only known when the
+// selectors are expanded.
+type variantNode struct {
+	baseNode
+	Function VariantFunc
+	Expanded node
+}
+
+func (n *variantNode) ExpandSelectors(parentSelectors []string) {
+	if len(parentSelectors) == 0 {
+		parentSelectors = []string{""}
+	}
+
+	variant := n.Function(parentSelectors[0])
+	if strings.HasPrefix(variant, "@") {
+		atRule := newAtRuleNode(variant, n.LineNumber)
+		atRule.Children = n.Children
+		atRule.ExpandSelectors(parentSelectors)
+
+		n.Expanded = atRule
+		return
+	}
+
+	selectors := appendSelectors(nil, variant)
+	for _, parentSelector := range parentSelectors[1:] {
+		selectors = appendSelectors(selectors, n.Function(parentSelector))
+	}
+
+	rule := newSelectorNode(selectors, n.LineNumber)
+	rule.Children = n.Children
+	rule.MergedSelectors = selectors
+	for _, child := range rule.Children {
+		child.ExpandSelectors(selectors)
+	}
+
+	n.Expanded = rule
+}
+
+func (n *variantNode) HideIfEmpty() bool {
+	if n.Expanded == nil {
+		n.Hidden = true
+		return true
+	}
+
+	n.Hidden = n.Expanded.HideIfEmpty()
+	return n.Hidden
+}
+
+func (n *variantNode) GetCopy() node {
+	copy := newVariantNode(n.Function, n.LineNumber)
+
+	for _, child := range n.Children {
+		copy.Children = append(copy.Children, child.GetCopy())
+	}
+
+	return copy
+}
+
+func (n *variantNode) Render(w io.Writer) {
+	if n.Hidden {
+		return
+	}
+
+	n.Expanded.Render(w)
+}
+
+func (n *variantNode) Dump(indent string) {
+	fmt.Printf("%sVariantNode\n", indent)
+	for _, child := range n.Children {
+		child.Dump(indent + "  ")
+	}
+}
+
 type context struct {
 	ParentContext *context
 	Node          node
@@ -502,6 +675,20 @@ func newImportNode(text string, lineNumber int) *importNode {
 	return &n
 }
 
+func newTailwindNode(directive string, text string, lineNumber int) *tailwindNode {
+	n := tailwindNode{Directive: directive, Text: text}
+	n.Children = make([]node, 0)
+	n.LineNumber = lineNumber
+	return &n
+}
+
+func newVariantNode(function VariantFunc, lineNumber int) *variantNode {
+	n := variantNode{Function: function}
+	n.Children = make([]node, 0)
+	n.LineNumber = lineNumber
+	return &n
+}
+
 func newContext(node node, parentContext *context) *context {
 	context := context{parentContext, node}
 	return &context
@@ -545,6 +732,19 @@ func (p *parser) BuildTree(elements *elements) (*rootNode, error) {
 			context.AddChild(importNode)
 		}
 
+		if elementType == typeTailwind {
+			if context.ParentContext != nil {
+				return nil, fmt.Errorf("Line %d: @tailwind is only allowed at the top level", lineNumber)
+			}
+
+			tailwindNode, err := p.newTailwindNode(text, lineNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			context.AddChild(tailwindNode)
+		}
+
 		if elementType == typeOpenBrace {
 			previousElement := elements.Items[index-1]
 			previousType := previousElement.ElementType
@@ -619,6 +819,38 @@ func splitDeclarations(str string) []string {
 	}
 }
 
+// hasParentReference reports whether a selector contains a & that isn't
+// escaped, like the one in the class name .\[\&\>svg\]\:w-4.
+func hasParentReference(selector string) bool {
+	for i := 0; i < len(selector); i++ {
+		if selector[i] == '\\' {
+			i++
+		} else if selector[i] == '&' {
+			return true
+		}
+	}
+
+	return false
+}
+
+func replaceParentReference(selector string, parentSelector string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(selector); i++ {
+		switch {
+		case selector[i] == '\\' && i+1 < len(selector):
+			b.WriteString(selector[i : i+2])
+			i++
+		case selector[i] == '&':
+			b.WriteString(parentSelector)
+		default:
+			b.WriteByte(selector[i])
+		}
+	}
+
+	return b.String()
+}
+
 func mergeSelectors(parentSelectors []string, childSelectors []string) []string {
 	selectors := make([]string, 0)
 
@@ -629,8 +861,8 @@ func mergeSelectors(parentSelectors []string, childSelectors []string) []string
 
 		for _, parentSelector := range parentSelectors {
 			var selector string
-			if strings.Contains(childSelector, "&") {
-				selector = strings.ReplaceAll(childSelector, "&", parentSelector)
+			if hasParentReference(childSelector) {
+				selector = replaceParentReference(childSelector, parentSelector)
 			} else if parentSelector != "" {
 				selector = parentSelector + " " + childSelector
 			} else {
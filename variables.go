@@ -2,8 +2,8 @@ package tailless
 
 import "fmt"
 
-func resolveVariables(tree *rootNode) error {
-	colorVariables := variablesCollection{Items: *colors}
+func resolveVariables(tree *rootNode, theme *theme) error {
+	colorVariables := variablesCollection{Items: theme.Colors, Theme: theme}
 	return recursiveResolveVariables(tree, &colorVariables)
 }
 
@@ -29,10 +29,15 @@ func recursiveResolveVariables(node node, parentVariables *variablesCollection)
 type variablesCollection struct {
 	Parent *variablesCollection
 	Items  map[string]string
+	Theme  *theme
 }
 
 func newVariablesCollection(parent *variablesCollection) *variablesCollection {
 	variables := variablesCollection{Parent: parent}
+	if parent != nil {
+		variables.Theme = parent.Theme
+	}
+
 	variables.Items = make(map[string]string)
 	return &variables
 }
@@ -50,6 +55,21 @@ func (v *variablesCollection) Get(name string) string {
 	return ""
 }
 
+// ReplaceFunctions resolves the theme() functions in a value and, in
+// at-rules, the screen() functions.
+func (v *variablesCollection) ReplaceFunctions(text string, atRule bool) (string, error) {
+	if v.Theme == nil {
+		return text, nil
+	}
+
+	text, err := v.Theme.ReplaceFunctions(text)
+	if err != nil || !atRule {
+		return text, err
+	}
+
+	return v.Theme.ReplaceScreens(text)
+}
+
 func (v *variablesCollection) Read(n node) {
 	for _, child := range n.GetChildren() {
 		child.GetVariable(v)